@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newClientCertificate returns a self-signed client certificate and a pool
+// which trusts it.
+func newClientCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform-pi"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := tls.X509KeyPair(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert.Leaf)
+	return cert, pool
+}
+
+func TestClientCertificateAuthentication(t *testing.T) {
+	cert, pool := newClientCertificate(t)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/session/create" {
+			t.Error("a session should not be created with certificate authentication")
+		}
+		if _, _, ok := r.BasicAuth(); ok {
+			t.Error("basic auth should not be sent with certificate authentication")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"result_count": 0, "results": []}`)
+	}))
+	ts.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  pool,
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, "", "", WithInsecure(true), WithClientCertificate(cert))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Session != nil {
+		t.Fatal("expected no session authenticator with certificate authentication")
+	}
+	rsp, err := c.ListSegmentPorts(context.Background(), "seg", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rsp.StatusCode)
+	}
+
+	// Without the certificate the TLS handshake is refused.
+	c, err = NewClient(ts.URL, "", "", WithInsecure(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp, err := c.ListSegmentPorts(context.Background(), "seg", nil); err == nil {
+		rsp.Body.Close()
+		t.Fatal("expected the request to fail without a client certificate")
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultOrgId is the only organization NSX currently supports.
+const DefaultOrgId = "default"
+
+// DefaultEnforcementPointId is the enforcement point of a Federation site's
+// Local Manager.
+const DefaultEnforcementPointId = "default"
+
+// DefaultSiteId is the site of an NSX Manager which isn't part of a
+// Federation.
+const DefaultSiteId = "default"
+
+// PolicyContext selects the NSX tenancy that objects are managed in. The
+// zero value is the default space, /infra. Setting ProjectId manages
+// objects in an NSX 4.x project, and additionally setting VpcId manages
+// them in a VPC of that project, where segments are VPC subnets.
+//
+// Setting GlobalManager instead manages the global objects of an NSX
+// Federation, such as stretched segments, through the Global Manager API
+// at /global-manager/api/v1/global-infra.
+type PolicyContext struct {
+	OrgId     string
+	ProjectId string
+	VpcId     string
+
+	GlobalManager bool
+}
+
+// Validate reports contexts which can't be turned into a path.
+func (pc PolicyContext) Validate() error {
+	if pc.VpcId != "" && pc.ProjectId == "" {
+		return fmt.Errorf("a VPC context requires a project")
+	}
+	if pc.OrgId != "" && pc.ProjectId == "" {
+		return fmt.Errorf("an org context requires a project")
+	}
+	if pc.GlobalManager && pc.ProjectId != "" {
+		return fmt.Errorf("projects and VPCs are not supported by the NSX Global Manager")
+	}
+	return nil
+}
+
+// normalized returns pc with the default org as an empty OrgId, so that
+// equivalent contexts compare equal.
+func (pc PolicyContext) normalized() PolicyContext {
+	if pc.OrgId == DefaultOrgId {
+		pc.OrgId = ""
+	}
+	return pc
+}
+
+// IsVpc reports whether objects are managed in a VPC.
+func (pc PolicyContext) IsVpc() bool {
+	return pc.VpcId != ""
+}
+
+func (pc PolicyContext) orgId() string {
+	if pc.OrgId == "" {
+		return DefaultOrgId
+	}
+	return pc.OrgId
+}
+
+// APIPath returns the path of the API that requests are made to, which the
+// policy paths of objects are appended to.
+func (pc PolicyContext) APIPath() string {
+	if pc.GlobalManager {
+		return "/global-manager/api/v1"
+	}
+	return "/policy/api/v1"
+}
+
+// InfraPath returns the policy path that hierarchical requests and the
+// segments of the context are rooted at.
+func (pc PolicyContext) InfraPath() string {
+	switch {
+	case pc.GlobalManager:
+		return "/global-infra"
+	case pc.VpcId != "":
+		return "/orgs/" + pc.orgId() + "/projects/" + pc.ProjectId + "/vpcs/" + pc.VpcId
+	case pc.ProjectId != "":
+		return "/orgs/" + pc.orgId() + "/projects/" + pc.ProjectId + "/infra"
+	}
+	return "/infra"
+}
+
+// SegmentsPath returns the policy path that the segments of the context, or
+// the subnets of a VPC context, are listed at.
+func (pc PolicyContext) SegmentsPath() string {
+	if pc.VpcId != "" {
+		return pc.InfraPath() + "/subnets"
+	}
+	return pc.InfraPath() + "/segments"
+}
+
+// SegmentPath returns the policy path of a segment, or of a subnet in a
+// VPC context.
+func (pc PolicyContext) SegmentPath(segment_id string) string {
+	return pc.SegmentsPath() + "/" + segment_id
+}
+
+// SegmentPortPath returns the policy path of a segment port.
+func (pc PolicyContext) SegmentPortPath(segment_id string, port_id string) string {
+	return pc.SegmentPath(segment_id) + "/ports/" + port_id
+}
+
+// Tier1Path returns the policy path of a tier-1 gateway. Tier-1 gateways
+// aren't managed in a VPC, whose gateway is implicit.
+func (pc PolicyContext) Tier1Path(tier1_id string) string {
+	return pc.InfraPath() + "/tier-1s/" + tier1_id
+}
+
+// Tier1InterfacePath returns the policy path of a service interface of a
+// tier-1 gateway's locale service.
+func (pc PolicyContext) Tier1InterfacePath(tier1_id string, locale_service_id string, interface_id string) string {
+	return pc.Tier1Path(tier1_id) + "/locale-services/" + locale_service_id + "/interfaces/" + interface_id
+}
+
+// ParseTier1InterfacePath splits the policy path of a tier-1 service
+// interface, such as
+// /orgs/default/projects/dev/infra/tier-1s/t1/locale-services/default/interfaces/vlan-100,
+// into its context, tier-1 gateway, locale service and interface, as
+// splitPolicyPath does.
+func ParseTier1InterfacePath(policyPath string) (PolicyContext, string, string, string, error) {
+	pc, parts, ok := splitPolicyPath(policyPath)
+	if !ok || pc.IsVpc() || len(parts) != 6 || parts[0] != "tier-1s" || parts[2] != "locale-services" || parts[4] != "interfaces" ||
+		parts[1] == "" || parts[3] == "" || parts[5] == "" {
+		return PolicyContext{}, "", "", "", fmt.Errorf("%q is not the policy path of a tier-1 interface", policyPath)
+	}
+	return pc, parts[1], parts[3], parts[5], nil
+}
+
+// StaticRoutePath returns the policy path of a static route of a tier-1
+// gateway.
+func (pc PolicyContext) StaticRoutePath(tier1_id string, route_id string) string {
+	return pc.Tier1Path(tier1_id) + "/static-routes/" + route_id
+}
+
+// ParseStaticRoutePath splits the policy path of a tier-1 static route,
+// such as /orgs/default/projects/dev/infra/tier-1s/t1/static-routes/vm-1,
+// into its context, tier-1 gateway and route, as splitPolicyPath does.
+func ParseStaticRoutePath(policyPath string) (PolicyContext, string, string, error) {
+	pc, parts, ok := splitPolicyPath(policyPath)
+	if !ok || pc.IsVpc() || len(parts) != 4 || parts[0] != "tier-1s" || parts[2] != "static-routes" || parts[1] == "" || parts[3] == "" {
+		return PolicyContext{}, "", "", fmt.Errorf("%q is not the policy path of a static route", policyPath)
+	}
+	return pc, parts[1], parts[3], nil
+}
+
+// TransportZonesPath returns the policy path that the transport zones of an
+// enforcement point of a site are listed at. An empty site or enforcement
+// point selects the default.
+func (pc PolicyContext) TransportZonesPath(site string, enforcement_point string) string {
+	if site == "" {
+		site = DefaultSiteId
+	}
+	if enforcement_point == "" {
+		enforcement_point = DefaultEnforcementPointId
+	}
+	return pc.InfraPath() + "/sites/" + site + "/enforcement-points/" + enforcement_point + "/transport-zones"
+}
+
+// EnforcementPointPath returns the policy path of an enforcement point of a
+// Federation site, which selects the Local Manager that a Global Manager
+// read is served by. An empty enforcement point selects the default.
+func EnforcementPointPath(site string, enforcement_point string) string {
+	if enforcement_point == "" {
+		enforcement_point = DefaultEnforcementPointId
+	}
+	return "/global-infra/sites/" + site + "/enforcement-points/" + enforcement_point
+}
+
+// WithEnforcementPoint returns a RequestEditorFn which has a Global Manager
+// read served by the Local Manager at enforcement_point_path, as returned by
+// EnforcementPointPath, rather than from the global intent.
+func WithEnforcementPoint(enforcement_point_path string) RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		queryValues := req.URL.Query()
+		queryValues.Set("enforcement_point_path", enforcement_point_path)
+		req.URL.RawQuery = queryValues.Encode()
+		return nil
+	}
+}
+
+// splitPolicyPath splits a policy path, such as
+// /orgs/default/projects/dev/infra/segments/web, into its context and the
+// path elements below the context's InfraPath. The default org is returned
+// as an empty OrgId, and paths below /global-infra are returned in a
+// GlobalManager context. ok is false for a path outside of any context.
+func splitPolicyPath(policyPath string) (pc PolicyContext, parts []string, ok bool) {
+	parts = strings.Split(strings.TrimPrefix(policyPath, "/"), "/")
+
+	if len(parts) >= 4 && parts[0] == "orgs" && parts[2] == "projects" {
+		pc.ProjectId = parts[3]
+		if parts[1] != DefaultOrgId {
+			pc.OrgId = parts[1]
+		}
+		parts = parts[4:]
+		if len(parts) >= 2 && parts[0] == "vpcs" {
+			pc.VpcId = parts[1]
+			return pc, parts[2:], true
+		}
+	}
+
+	if len(parts) > 0 && parts[0] == "infra" {
+		return pc, parts[1:], true
+	}
+	if len(parts) > 0 && parts[0] == "global-infra" && pc.ProjectId == "" {
+		pc.GlobalManager = true
+		return pc, parts[1:], true
+	}
+	return PolicyContext{}, nil, false
+}
+
+// ParseSegmentPath splits the policy path of a segment, such as
+// /orgs/default/projects/dev/infra/segments/web, into its context and
+// segment, as splitPolicyPath does.
+func ParseSegmentPath(policyPath string) (PolicyContext, string, error) {
+	pc, parts, ok := splitPolicyPath(policyPath)
+	if !ok || len(parts) != 2 || parts[0] != segmentsCollection(pc) || parts[1] == "" {
+		return PolicyContext{}, "", fmt.Errorf("%q is not the policy path of a segment", policyPath)
+	}
+	return pc, parts[1], nil
+}
+
+// ParseSegmentPortPath splits the policy path of a segment port, such as
+// /orgs/default/projects/dev/infra/segments/web/ports/vm-1, into its
+// context, segment and port, as splitPolicyPath does.
+func ParseSegmentPortPath(policyPath string) (PolicyContext, string, string, error) {
+	pc, parts, ok := splitPolicyPath(policyPath)
+	if !ok || len(parts) != 4 || parts[0] != segmentsCollection(pc) || parts[2] != "ports" || parts[1] == "" || parts[3] == "" {
+		return PolicyContext{}, "", "", fmt.Errorf("%q is not the policy path of a segment port", policyPath)
+	}
+	return pc, parts[1], parts[3], nil
+}
+
+// segmentsCollection returns the last element of pc's SegmentsPath.
+func segmentsCollection(pc PolicyContext) string {
+	if pc.IsVpc() {
+		return "subnets"
+	}
+	return "segments"
+}
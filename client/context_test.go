@@ -0,0 +1,181 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSegmentPortPath(t *testing.T) {
+	tests := []struct {
+		pc   PolicyContext
+		path string
+	}{
+		{PolicyContext{}, "/infra/segments/seg/ports/port"},
+		{PolicyContext{ProjectId: "dev"}, "/orgs/default/projects/dev/infra/segments/seg/ports/port"},
+		{PolicyContext{OrgId: "org", ProjectId: "dev", VpcId: "vpc"}, "/orgs/org/projects/dev/vpcs/vpc/subnets/seg/ports/port"},
+		{PolicyContext{GlobalManager: true}, "/global-infra/segments/seg/ports/port"},
+	}
+	for _, test := range tests {
+		if got := test.pc.SegmentPortPath("seg", "port"); got != test.path {
+			t.Errorf("expected %s, got %s", test.path, got)
+		}
+
+		pc, segment_id, port_id, err := ParseSegmentPortPath(test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pc.SegmentPortPath(segment_id, port_id) != test.path {
+			t.Errorf("%s did not round trip: %+v %s %s", test.path, pc, segment_id, port_id)
+		}
+	}
+
+	for _, invalid := range []string{
+		"seg/port",
+		"/infra/segments/seg",
+		"/infra/segments/seg/ports/port/extra",
+		"/orgs/default/projects/dev/vpcs/vpc/segments/seg/ports/port",
+		"/orgs/default/projects/dev/segments/seg/ports/port",
+		"/infra/tier-1s/t1/ports/port",
+		"/orgs/default/projects/dev/global-infra/segments/seg/ports/port",
+	} {
+		if _, _, _, err := ParseSegmentPortPath(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+
+	if err := (PolicyContext{VpcId: "vpc"}).Validate(); err == nil {
+		t.Error("expected a VPC without a project to be rejected")
+	}
+	if err := (PolicyContext{GlobalManager: true, ProjectId: "dev"}).Validate(); err == nil {
+		t.Error("expected a Global Manager project to be rejected")
+	}
+}
+
+func TestSegmentPath(t *testing.T) {
+	for _, pc := range []PolicyContext{
+		{},
+		{ProjectId: "dev"},
+		{OrgId: "org", ProjectId: "dev", VpcId: "vpc"},
+		{GlobalManager: true},
+	} {
+		p := pc.SegmentPath("seg")
+		parsed, segment_id, err := ParseSegmentPath(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed != pc || segment_id != "seg" {
+			t.Errorf("%s did not round trip: %+v %s", p, parsed, segment_id)
+		}
+	}
+
+	for _, invalid := range []string{
+		"seg",
+		"/infra/segments",
+		"/infra/segments/seg/ports/port",
+		"/orgs/default/projects/dev/segments/seg",
+		"/orgs/default/projects/dev/vpcs/vpc/segments/seg",
+	} {
+		if _, _, err := ParseSegmentPath(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestTier1InterfacePath(t *testing.T) {
+	tests := []struct {
+		pc   PolicyContext
+		path string
+	}{
+		{PolicyContext{}, "/infra/tier-1s/t1/locale-services/default/interfaces/vlan-100"},
+		{PolicyContext{ProjectId: "dev"}, "/orgs/default/projects/dev/infra/tier-1s/t1/locale-services/default/interfaces/vlan-100"},
+		{PolicyContext{GlobalManager: true}, "/global-infra/tier-1s/t1/locale-services/default/interfaces/vlan-100"},
+	}
+	for _, test := range tests {
+		if got := test.pc.Tier1InterfacePath("t1", "default", "vlan-100"); got != test.path {
+			t.Errorf("expected %s, got %s", test.path, got)
+		}
+
+		pc, tier1_id, locale_service_id, interface_id, err := ParseTier1InterfacePath(test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pc != test.pc || tier1_id != "t1" || locale_service_id != "default" || interface_id != "vlan-100" {
+			t.Errorf("%s did not round trip: %+v %s %s %s", test.path, pc, tier1_id, locale_service_id, interface_id)
+		}
+	}
+
+	for _, invalid := range []string{
+		"t1/default/vlan-100",
+		"/infra/tier-1s/t1/locale-services/default",
+		"/infra/tier-1s/t1/static-routes/default/interfaces/vlan-100",
+		"/orgs/default/projects/dev/vpcs/vpc/tier-1s/t1/locale-services/default/interfaces/vlan-100",
+	} {
+		if _, _, _, _, err := ParseTier1InterfacePath(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestStaticRoutePath(t *testing.T) {
+	for _, pc := range []PolicyContext{
+		{},
+		{ProjectId: "dev"},
+		{GlobalManager: true},
+	} {
+		p := pc.StaticRoutePath("t1", "vm-1")
+		parsed, tier1_id, route_id, err := ParseStaticRoutePath(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed != pc || tier1_id != "t1" || route_id != "vm-1" {
+			t.Errorf("%s did not round trip: %+v %s %s", p, parsed, tier1_id, route_id)
+		}
+	}
+
+	for _, invalid := range []string{
+		"t1/vm-1",
+		"/infra/tier-1s/t1/static-routes",
+		"/infra/tier-1s/t1/locale-services/vm-1",
+		"/orgs/default/projects/dev/vpcs/vpc/tier-1s/t1/static-routes/vm-1",
+	} {
+		if _, _, _, err := ParseStaticRoutePath(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestTransportZonesPath(t *testing.T) {
+	tests := []struct {
+		pc                      PolicyContext
+		site, enforcement_point string
+		path                    string
+	}{
+		{PolicyContext{}, "", "", "/infra/sites/default/enforcement-points/default/transport-zones"},
+		{PolicyContext{ProjectId: "dev"}, "", "", "/orgs/default/projects/dev/infra/sites/default/enforcement-points/default/transport-zones"},
+		{PolicyContext{GlobalManager: true}, "paris", "", "/global-infra/sites/paris/enforcement-points/default/transport-zones"},
+	}
+	for _, test := range tests {
+		if got := test.pc.TransportZonesPath(test.site, test.enforcement_point); got != test.path {
+			t.Errorf("expected %s, got %s", test.path, got)
+		}
+	}
+}
+
+func TestGlobalManagerRequest(t *testing.T) {
+	pc := PolicyContext{GlobalManager: true}
+	req, err := NewListSegmentPortsRequest("https://gm.example.com", pc, "stretched", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WithEnforcementPoint(EnforcementPointPath("paris", ""))(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "https://gm.example.com/global-manager/api/v1/global-infra/segments/stretched/ports?enforcement_point_path=%2Fglobal-infra%2Fsites%2Fparis%2Fenforcement-points%2Fdefault"
+	if req.URL.String() != expected {
+		t.Errorf("expected %s, got %s", expected, req.URL)
+	}
+}
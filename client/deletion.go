@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DetachSegmentPort removes the attachment of a segment port, so that NSX
+// allows the port to be deleted while a VIF is still connected to it. It
+// does nothing if the port doesn't exist or has no attachment.
+//
+// The port is read and written back without its attachment, rather than
+// decoded into a SegmentPort, so that fields unknown to this client are
+// preserved.
+func (c *Client) DetachSegmentPort(ctx context.Context, segment_id string, port_id string, reqEditors ...RequestEditorFn) error {
+	rsp, err := c.GetSegmentPort(ctx, segment_id, port_id, reqEditors...)
+	if err != nil {
+		return err
+	}
+	if rsp.StatusCode == http.StatusNotFound {
+		rsp.Body.Close()
+		return nil
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return ParseAPIError(rsp)
+	}
+
+	var port map[string]any
+	err = json.NewDecoder(rsp.Body).Decode(&port)
+	rsp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("invalid format received for segment port %s: %w", port_id, err)
+	}
+	if _, ok := port["attachment"]; !ok {
+		return nil
+	}
+	delete(port, "attachment")
+
+	req, err := newPolicyObjectRequest(c.Server, c.PolicyContext, http.MethodPut, c.PolicyContext.SegmentPortPath(segment_id, port_id), port)
+	if err != nil {
+		return err
+	}
+	rsp, err = c.do(ctx, req, reqEditors)
+	if err != nil {
+		return err
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return ParseAPIError(rsp)
+	}
+	rsp.Body.Close()
+	return nil
+}
+
+// WaitForSegmentPortDeletion polls a segment port every interval until NSX
+// no longer returns it. Cancelling ctx, such as with a timeout, abandons the
+// wait.
+func (c *Client) WaitForSegmentPortDeletion(ctx context.Context, segment_id string, port_id string, interval time.Duration) error {
+	for {
+		rsp, err := c.GetSegmentPort(ctx, segment_id, port_id)
+		if err != nil {
+			return err
+		}
+		switch rsp.StatusCode {
+		case http.StatusNotFound:
+			rsp.Body.Close()
+			return nil
+		case http.StatusOK:
+			rsp.Body.Close()
+		default:
+			return ParseAPIError(rsp)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("timed out waiting for NSX to delete segment port %s: %w", port_id, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxRawErrorLength limits how much of a response body without an error
+// envelope is included in an APIError.
+const maxRawErrorLength = 1024
+
+// ErrorCodeObjectBusy is the NSX error code of a 409 Conflict returned
+// while another operation holds a lock on the object, which clears once
+// that operation completes.
+const ErrorCodeObjectBusy = 500105
+
+// APIError is the error envelope returned by the NSX API for failed
+// requests.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response. It is not part of
+	// the envelope and is zero for related errors.
+	StatusCode int `json:"-"`
+
+	HttpStatus    string     `json:"httpStatus,omitempty"`
+	ErrorCode     int        `json:"error_code,omitempty"`
+	ErrorMessage  string     `json:"error_message,omitempty"`
+	ModuleName    string     `json:"module_name,omitempty"`
+	Details       string     `json:"details,omitempty"`
+	RelatedErrors []APIError `json:"related_errors,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	if e.StatusCode != 0 {
+		fmt.Fprintf(&b, "NSX API returned HTTP %d", e.StatusCode)
+	} else {
+		b.WriteString("NSX API error")
+	}
+	if e.ErrorCode != 0 {
+		fmt.Fprintf(&b, " (error code %d", e.ErrorCode)
+		if e.ModuleName != "" {
+			fmt.Fprintf(&b, ", module %s", e.ModuleName)
+		}
+		b.WriteString(")")
+	}
+	if e.ErrorMessage != "" {
+		b.WriteString(": ")
+		b.WriteString(e.ErrorMessage)
+	}
+	return b.String()
+}
+
+// ParseAPIError reads the error envelope from an unsuccessful response and
+// closes its body. Responses which do not contain an envelope, such as those
+// from a load balancer in front of NSX, are described by their status and
+// raw body instead.
+func ParseAPIError(rsp *http.Response) *APIError {
+	defer rsp.Body.Close()
+
+	apiErr := &APIError{}
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil || json.Unmarshal(body, apiErr) != nil || apiErr.ErrorMessage == "" {
+		if len(body) > maxRawErrorLength {
+			body = body[:maxRawErrorLength]
+		}
+		apiErr = &APIError{ErrorMessage: strings.TrimSpace(string(body))}
+		if apiErr.ErrorMessage == "" {
+			apiErr.ErrorMessage = http.StatusText(rsp.StatusCode)
+		}
+	}
+	apiErr.StatusCode = rsp.StatusCode
+	return apiErr
+}
@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseAPIError(t *testing.T) {
+	rsp := &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Body: io.NopCloser(strings.NewReader(`{
+			"httpStatus": "BAD_REQUEST",
+			"error_code": 500012,
+			"module_name": "Policy",
+			"error_message": "Invalid SegmentPort",
+			"related_errors": [
+				{"error_code": 503040, "module_name": "Policy", "error_message": "attachment.context_id is required for CHILD ports"}
+			]
+		}`)),
+	}
+
+	apiErr := ParseAPIError(rsp)
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.ErrorCode != 500012 {
+		t.Fatalf("unexpected error: %#v", apiErr)
+	}
+	if len(apiErr.RelatedErrors) != 1 || apiErr.RelatedErrors[0].ErrorCode != 503040 {
+		t.Fatalf("expected one related error, got %#v", apiErr.RelatedErrors)
+	}
+	want := "NSX API returned HTTP 400 (error code 500012, module Policy): Invalid SegmentPort"
+	if apiErr.Error() != want {
+		t.Fatalf("expected %q, got %q", want, apiErr.Error())
+	}
+}
+
+func TestParseAPIErrorWithoutEnvelope(t *testing.T) {
+	rsp := &http.Response{
+		StatusCode: http.StatusBadGateway,
+		Body:       io.NopCloser(strings.NewReader("")),
+	}
+
+	apiErr := ParseAPIError(rsp)
+	if apiErr.ErrorMessage != "Bad Gateway" {
+		t.Fatalf("expected the status text as the message, got %q", apiErr.ErrorMessage)
+	}
+}
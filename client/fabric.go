@@ -0,0 +1,212 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// VirtualMachine is a VM in the NSX fabric inventory, as discovered from
+// its compute manager.
+type VirtualMachine struct {
+	DisplayName string `json:"display_name"`
+	// ExternalId is the VM's BIOS UUID, which its VIFs refer to it by.
+	ExternalId    string `json:"external_id"`
+	HostId        string `json:"host_id,omitempty"`
+	LocalIdOnHost string `json:"local_id_on_host,omitempty"`
+	PowerState    string `json:"power_state,omitempty"`
+	ResourceType  string `json:"resource_type"`
+	Tags          []Tag  `json:"tags,omitempty"`
+	Type          string `json:"type,omitempty"`
+}
+
+// VirtualNetworkInterface is a VIF, a virtual NIC of a VM. A segment port
+// attaches the VIF whose LportAttachmentId is the port's attachment id.
+type VirtualNetworkInterface struct {
+	DeviceKey         string `json:"device_key,omitempty"`
+	DeviceName        string `json:"device_name,omitempty"`
+	DisplayName       string `json:"display_name,omitempty"`
+	ExternalId        string `json:"external_id"`
+	HostId            string `json:"host_id,omitempty"`
+	LportAttachmentId string `json:"lport_attachment_id,omitempty"`
+	MacAddress        string `json:"mac_address,omitempty"`
+	// OwnerVmId is the ExternalId of the VM the VIF belongs to.
+	OwnerVmId    string `json:"owner_vm_id"`
+	ResourceType string `json:"resource_type"`
+}
+
+// ListVirtualMachinesParams defines the optional query parameters for
+// ListVirtualMachines.
+type ListVirtualMachinesParams struct {
+	// Cursor is the opaque cursor returned with the previous page.
+	Cursor string
+	// PageSize is the maximum number of results per page. NSX defaults to 1000.
+	PageSize int
+	// DisplayName and ExternalId only return the VMs with that name or id.
+	DisplayName string
+	ExternalId  string
+}
+
+// ListVifsParams defines the optional query parameters for ListVifs.
+type ListVifsParams struct {
+	// Cursor is the opaque cursor returned with the previous page.
+	Cursor string
+	// PageSize is the maximum number of results per page. NSX defaults to 1000.
+	PageSize int
+	// OwnerVmId only returns the VIFs of the VM with that external id.
+	OwnerVmId string
+	// LportAttachmentId only returns the VIF with that attachment id.
+	LportAttachmentId string
+}
+
+type ListVirtualMachinesResponse struct {
+	Cursor      string           `json:"cursor"`
+	ResultCount int              `json:"result_count"`
+	Results     []VirtualMachine `json:"results"`
+}
+
+type ListVifsResponse struct {
+	Cursor      string                    `json:"cursor"`
+	ResultCount int                       `json:"result_count"`
+	Results     []VirtualNetworkInterface `json:"results"`
+}
+
+// ListVirtualMachines lists VMs from the fabric inventory. The inventory is
+// part of the NSX Manager API rather than the policy API, so it isn't
+// scoped to the client's policy context.
+func (c *Client) ListVirtualMachines(ctx context.Context, params *ListVirtualMachinesParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListVirtualMachinesRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewListVirtualMachinesRequest(server string, params *ListVirtualMachinesParams) (*http.Request, error) {
+	queryValues := url.Values{}
+	if params != nil {
+		setPageParams(queryValues, params.Cursor, params.PageSize)
+		if params.DisplayName != "" {
+			queryValues.Set("display_name", params.DisplayName)
+		}
+		if params.ExternalId != "" {
+			queryValues.Set("external_id", params.ExternalId)
+		}
+	}
+	return newListRequest(server, "/api/v1/fabric/virtual-machines", queryValues)
+}
+
+// VirtualMachines iterates over every VM matching params, following the
+// cursor from page to page. Iteration stops after the first error.
+func (c *Client) VirtualMachines(ctx context.Context, params *ListVirtualMachinesParams, reqEditors ...RequestEditorFn) iter.Seq2[VirtualMachine, error] {
+	return paginate[VirtualMachine](func(cursor string) (*http.Response, error) {
+		var page ListVirtualMachinesParams
+		if params != nil {
+			page = *params
+		}
+		if cursor != "" {
+			page.Cursor = cursor
+		}
+		return c.ListVirtualMachines(ctx, &page, reqEditors...)
+	}, "virtual machines")
+}
+
+// ListAllVirtualMachines returns every VM matching params, reading all pages.
+func (c *Client) ListAllVirtualMachines(ctx context.Context, params *ListVirtualMachinesParams, reqEditors ...RequestEditorFn) ([]VirtualMachine, error) {
+	var vms []VirtualMachine
+	for vm, err := range c.VirtualMachines(ctx, params, reqEditors...) {
+		if err != nil {
+			return nil, err
+		}
+		vms = append(vms, vm)
+	}
+	return vms, nil
+}
+
+// ListVifs lists VIFs from the fabric inventory, which like VMs isn't
+// scoped to the client's policy context.
+func (c *Client) ListVifs(ctx context.Context, params *ListVifsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListVifsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewListVifsRequest(server string, params *ListVifsParams) (*http.Request, error) {
+	queryValues := url.Values{}
+	if params != nil {
+		setPageParams(queryValues, params.Cursor, params.PageSize)
+		if params.OwnerVmId != "" {
+			queryValues.Set("owner_vm_id", params.OwnerVmId)
+		}
+		if params.LportAttachmentId != "" {
+			queryValues.Set("lport_attachment_id", params.LportAttachmentId)
+		}
+	}
+	return newListRequest(server, "/api/v1/fabric/vifs", queryValues)
+}
+
+// Vifs iterates over every VIF matching params, following the cursor from
+// page to page. Iteration stops after the first error.
+func (c *Client) Vifs(ctx context.Context, params *ListVifsParams, reqEditors ...RequestEditorFn) iter.Seq2[VirtualNetworkInterface, error] {
+	return paginate[VirtualNetworkInterface](func(cursor string) (*http.Response, error) {
+		var page ListVifsParams
+		if params != nil {
+			page = *params
+		}
+		if cursor != "" {
+			page.Cursor = cursor
+		}
+		return c.ListVifs(ctx, &page, reqEditors...)
+	}, "VIFs")
+}
+
+// ListAllVifs returns every VIF matching params, reading all pages.
+func (c *Client) ListAllVifs(ctx context.Context, params *ListVifsParams, reqEditors ...RequestEditorFn) ([]VirtualNetworkInterface, error) {
+	var vifs []VirtualNetworkInterface
+	for vif, err := range c.Vifs(ctx, params, reqEditors...) {
+		if err != nil {
+			return nil, err
+		}
+		vifs = append(vifs, vif)
+	}
+	return vifs, nil
+}
+
+func setPageParams(queryValues url.Values, cursor string, pageSize int) {
+	if cursor != "" {
+		queryValues.Set("cursor", cursor)
+	}
+	if pageSize > 0 {
+		queryValues.Set("page_size", strconv.Itoa(pageSize))
+	}
+}
+
+// newListRequest builds a GET request for a list at operationPath.
+func newListRequest(server string, operationPath string, queryValues url.Values) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+	queryURL.RawQuery = queryValues.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
@@ -0,0 +1,222 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// DefaultNodeDownTime is how long an unreachable NSX Manager node is skipped
+// before requests are routed to it again.
+const DefaultNodeDownTime = 30 * time.Second
+
+// FailoverDoer wraps an HttpRequestDoer, sending requests to one node of an
+// NSX Manager cluster and failing over to the next healthy node when the
+// node can't be reached. Requests are built against any of the nodes; only
+// their scheme and host are rewritten.
+//
+// A node is health-checked before requests are routed to it, so that nodes
+// which accept connections but are still upgrading are skipped.
+type FailoverDoer struct {
+	Doer  HttpRequestDoer
+	Nodes []*url.URL
+
+	// DownTime is how long a node which failed is skipped.
+	DownTime time.Duration
+
+	mu        sync.Mutex
+	active    int
+	checked   bool
+	downUntil map[int]time.Time
+}
+
+// NewFailoverDoer returns a FailoverDoer for the given nodes, which are
+// tried in order. Duplicate nodes are ignored.
+func NewFailoverDoer(doer HttpRequestDoer, nodes ...string) (*FailoverDoer, error) {
+	d := &FailoverDoer{
+		Doer:      doer,
+		DownTime:  DefaultNodeDownTime,
+		downUntil: map[int]time.Time{},
+	}
+	seen := map[string]bool{}
+	for _, node := range nodes {
+		u, err := url.Parse(node)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("NSX Manager node %q must be an absolute URL", node)
+		}
+		if !seen[u.Host] {
+			seen[u.Host] = true
+			d.Nodes = append(d.Nodes, u)
+		}
+	}
+	if len(d.Nodes) == 0 {
+		return nil, fmt.Errorf("no NSX Manager nodes configured")
+	}
+	return d, nil
+}
+
+func (d *FailoverDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	var errs []error
+	sent := false
+	for _, i := range d.candidates() {
+		node := d.Nodes[i]
+
+		// the body of the first attempt is consumed, so it can only be
+		// replayed on another node if it can be rewound
+		if sent && req.Body != nil && req.GetBody == nil {
+			break
+		}
+
+		if !d.isActive(i) {
+			if err := d.checkHealth(req, node); err != nil {
+				if ctx.Err() != nil {
+					return nil, err
+				}
+				d.markDown(req, i, err)
+				errs = append(errs, err)
+				continue
+			}
+		}
+
+		next := req.Clone(ctx)
+		next.URL.Scheme = node.Scheme
+		next.URL.Host = node.Host
+		next.Host = ""
+		if sent && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			next.Body = body
+		}
+
+		rsp, err := d.Doer.Do(next)
+		sent = true
+		if err == nil {
+			d.markActive(i)
+			tflog.Debug(ctx, "NSX Manager node served request", map[string]any{
+				"nsx_node": node.Host,
+				"method":   req.Method,
+				"path":     req.URL.Path,
+				"status":   rsp.StatusCode,
+			})
+			return rsp, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		d.markDown(req, i, err)
+		errs = append(errs, err)
+	}
+	return nil, fmt.Errorf("no NSX Manager node could serve %s %s: %w", req.Method, req.URL.Path, errors.Join(errs...))
+}
+
+// candidates returns the order in which nodes are tried: the active node,
+// then every other node which isn't known to be down. If every node is
+// down they are all tried anyway.
+func (d *FailoverDoer) candidates() []int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	var up, down []int
+	for n := range d.Nodes {
+		i := (d.active + n) % len(d.Nodes)
+		if now.Before(d.downUntil[i]) {
+			down = append(down, i)
+		} else {
+			up = append(up, i)
+		}
+	}
+	if len(up) == 0 {
+		return down
+	}
+	return up
+}
+
+// isActive reports whether node i is the node which served the last
+// request, and so doesn't need to be health-checked.
+func (d *FailoverDoer) isActive(i int) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.checked && i == d.active && d.downUntil[i].IsZero()
+}
+
+func (d *FailoverDoer) markActive(i int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.active = i
+	d.checked = true
+	delete(d.downUntil, i)
+}
+
+func (d *FailoverDoer) markDown(req *http.Request, i int, err error) {
+	d.mu.Lock()
+	d.downUntil[i] = time.Now().Add(d.DownTime)
+	d.mu.Unlock()
+
+	tflog.Warn(req.Context(), "NSX Manager node is unavailable, failing over", map[string]any{
+		"nsx_node": d.Nodes[i].Host,
+		"error":    err.Error(),
+	})
+}
+
+// checkHealth asks node whether it can serve API requests, authenticating
+// the same way as req. A node which rejects the credentials is considered
+// healthy, as the session is renewed when the request itself is rejected.
+func (d *FailoverDoer) checkHealth(req *http.Request, node *url.URL) error {
+	healthURL, err := node.Parse("/api/v1/reverse-proxy/node/health")
+	if err != nil {
+		return err
+	}
+	check, err := http.NewRequestWithContext(req.Context(), http.MethodGet, healthURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	for _, header := range []string{"Authorization", "Cookie", xsrfTokenHeader} {
+		if v := req.Header.Get(header); v != "" {
+			check.Header.Set(header, v)
+		}
+	}
+
+	rsp, err := d.Doer.Do(check)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	switch rsp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil
+	default:
+		return fmt.Errorf("NSX Manager node %s health check failed: %s", node.Host, rsp.Status)
+	}
+
+	var health struct {
+		Healthy bool `json:"healthy"`
+	}
+	if err := json.NewDecoder(rsp.Body).Decode(&health); err != nil {
+		return fmt.Errorf("invalid format received for NSX Manager node %s health: %w", node.Host, err)
+	}
+	if !health.Healthy {
+		return fmt.Errorf("NSX Manager node %s is not healthy", node.Host)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// testNode is an NSX Manager node which records the requests it receives.
+type testNode struct {
+	*httptest.Server
+
+	healthy bool
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func newTestNode(t *testing.T, healthy bool) *testNode {
+	n := &testNode{healthy: healthy}
+	n.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n.mu.Lock()
+		n.requests = append(n.requests, r.Method+" "+r.URL.Path)
+		n.mu.Unlock()
+
+		if r.URL.Path == "/api/v1/reverse-proxy/node/health" {
+			if !n.healthy {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = io.WriteString(w, `{"healthy": true}`)
+			return
+		}
+		if body, _ := io.ReadAll(r.Body); r.Method == http.MethodPatch && len(body) == 0 {
+			t.Error("expected the request body to be replayed")
+		}
+	}))
+	t.Cleanup(n.Close)
+	return n
+}
+
+func (n *testNode) Requests() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]string(nil), n.requests...)
+}
+
+func TestFailover(t *testing.T) {
+	down := newTestNode(t, true)
+	down.Close()
+	up := newTestNode(t, true)
+
+	c, err := NewClient(down.URL, "", "", WithFailoverNodes(up.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 {
+		rsp, err := c.PatchSegmentPort(context.Background(), PatchSegmentPortRequest{SegmentId: "seg", PortId: "port"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsp.Body.Close()
+	}
+
+	// the node is health-checked once, then used until it fails
+	expected := []string{
+		"GET /api/v1/reverse-proxy/node/health",
+		"PATCH /policy/api/v1/infra/segments/seg/ports/port",
+		"PATCH /policy/api/v1/infra/segments/seg/ports/port",
+	}
+	if requests := up.Requests(); !slices.Equal(requests, expected) {
+		t.Fatalf("expected requests %q, got %q", expected, requests)
+	}
+}
+
+func TestFailoverSkipsUnhealthyNodes(t *testing.T) {
+	upgrading := newTestNode(t, false)
+	up := newTestNode(t, true)
+
+	c, err := NewClient(upgrading.URL, "", "", WithFailoverNodes(up.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsp, err := c.GetSegmentPort(context.Background(), "seg", "port")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+
+	if requests := upgrading.Requests(); !slices.Equal(requests, []string{"GET /api/v1/reverse-proxy/node/health"}) {
+		t.Fatalf("expected only a health check of the unhealthy node, got %q", requests)
+	}
+	if requests := up.Requests(); len(requests) != 2 || requests[1] != "GET /policy/api/v1/infra/segments/seg/ports/port" {
+		t.Fatalf("expected the healthy node to serve the request, got %q", requests)
+	}
+}
+
+func TestFailoverNoNodes(t *testing.T) {
+	first := newTestNode(t, true)
+	first.Close()
+	second := newTestNode(t, false)
+
+	c, err := NewClient(first.URL, "", "", WithFailoverNodes(second.URL, first.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.GetSegmentPort(context.Background(), "seg", "port"); err == nil {
+		t.Fatal("expected an error when no node is available")
+	}
+}
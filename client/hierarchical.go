@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Infra is the root of a Hierarchical Policy API (H-API) request. Every
+// object below it is created, updated or deleted in a single transaction,
+// so either all of the changes are applied or none are.
+type Infra struct {
+	ResourceType string         `json:"resource_type"`
+	Children     []ChildSegment `json:"children"`
+}
+
+// ChildSegment wraps a segment and the ports below it in an H-API request.
+type ChildSegment struct {
+	ResourceType    string              `json:"resource_type"`
+	MarkedForDelete bool                `json:"marked_for_delete"`
+	Segment         HierarchicalSegment `json:"Segment"`
+}
+
+// HierarchicalSegment identifies the segment that the ports of a
+// ChildSegment belong to.
+type HierarchicalSegment struct {
+	Id           string             `json:"id"`
+	ResourceType string             `json:"resource_type"`
+	Children     []ChildSegmentPort `json:"children,omitempty"`
+}
+
+// ChildSegmentPort wraps a segment port in an H-API request. Ports which are
+// MarkedForDelete only need their Id and ResourceType set.
+type ChildSegmentPort struct {
+	ResourceType    string      `json:"resource_type"`
+	MarkedForDelete bool        `json:"marked_for_delete"`
+	SegmentPort     SegmentPort `json:"SegmentPort"`
+}
+
+// NewInfra returns an H-API request for the given segments.
+func NewInfra(segments ...ChildSegment) Infra {
+	return Infra{
+		ResourceType: "Infra",
+		Children:     segments,
+	}
+}
+
+// NewChildSegment wraps ports of the segment segment_id. The segment itself
+// is left unchanged.
+func NewChildSegment(segment_id string, ports ...ChildSegmentPort) ChildSegment {
+	return ChildSegment{
+		ResourceType: "ChildSegment",
+		Segment: HierarchicalSegment{
+			Id:           segment_id,
+			ResourceType: "Segment",
+			Children:     ports,
+		},
+	}
+}
+
+// NewChildSegmentPort wraps a port which is created or updated.
+func NewChildSegmentPort(port SegmentPort) ChildSegmentPort {
+	if port.ResourceType == "" {
+		port.ResourceType = "SegmentPort"
+	}
+	return ChildSegmentPort{
+		ResourceType: "ChildSegmentPort",
+		SegmentPort:  port,
+	}
+}
+
+// NewDeletedChildSegmentPort wraps a port which is deleted.
+func NewDeletedChildSegmentPort(port_id string) ChildSegmentPort {
+	return ChildSegmentPort{
+		ResourceType:    "ChildSegmentPort",
+		MarkedForDelete: true,
+		SegmentPort: SegmentPort{
+			Id:           port_id,
+			ResourceType: "SegmentPort",
+		},
+	}
+}
+
+// PatchInfra submits an H-API request.
+func (c *Client) PatchInfra(ctx context.Context, body Infra, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPatchInfraRequest(c.Server, c.PolicyContext, body)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewPatchInfraRequest(server string, pc PolicyContext, body Infra) (*http.Request, error) {
+	var err error
+
+	// VPCs have their own hierarchy of Child<Type> wrappers
+	if pc.IsVpc() {
+		return nil, fmt.Errorf("hierarchical requests are not supported in VPC %s", pc.VpcId)
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := pc.APIPath() + pc.InfraPath()
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+
+	req, err := http.NewRequest(http.MethodPatch, queryURL.String(), bodyReader)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+
+	return req, nil
+}
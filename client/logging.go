@@ -0,0 +1,213 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// LogSubsystem is the tflog subsystem that NSX API traffic is logged to.
+	LogSubsystem = "nsxt_ivr"
+
+	// LogLevelEnv sets the level of LogSubsystem independently of the rest
+	// of the provider. At DEBUG every request is logged with its status and
+	// latency; at TRACE the redacted headers and bodies are logged too.
+	LogLevelEnv = "TF_LOG_PROVIDER_NSXT_IVR"
+
+	redacted = "REDACTED"
+
+	// maxLoggedBodyLength limits how much of a body is logged.
+	maxLoggedBodyLength = 16 * 1024
+)
+
+// sensitiveHeaders are logged without their values.
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", xsrfTokenHeader}
+
+// sensitiveField matches the names of JSON and form fields whose values are
+// never logged.
+var sensitiveField = regexp.MustCompile(`(?i)password|passphrase|secret|private_key|token`)
+
+// LoggingDoer wraps an HttpRequestDoer, logging every request it sends to
+// the LogSubsystem tflog subsystem. Credentials, session cookies and XSRF
+// tokens are redacted, so that traces can be shared.
+type LoggingDoer struct {
+	Doer HttpRequestDoer
+
+	// LogBodies logs the headers and bodies of requests and responses at
+	// TRACE. Responses are buffered in memory to do so.
+	LogBodies bool
+}
+
+// NewLoggingDoer returns a LoggingDoer which logs bodies if TRACE logging is
+// enabled for the provider.
+func NewLoggingDoer(doer HttpRequestDoer) *LoggingDoer {
+	return &LoggingDoer{
+		Doer:      doer,
+		LogBodies: traceEnabled(),
+	}
+}
+
+// traceEnabled reports whether LogSubsystem logs at TRACE, checking the same
+// environment variables as Terraform from most to least specific.
+func traceEnabled() bool {
+	for _, env := range []string{LogLevelEnv, "TF_LOG_PROVIDER", "TF_LOG"} {
+		if level := os.Getenv(env); level != "" {
+			return strings.EqualFold(level, "TRACE") || strings.EqualFold(level, "JSON")
+		}
+	}
+	return false
+}
+
+func (d *LoggingDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx := tflog.NewSubsystem(req.Context(), LogSubsystem, tflog.WithLevelFromEnv(LogLevelEnv))
+
+	fields := map[string]any{
+		"http_method": req.Method,
+		"http_url":    req.URL.String(),
+	}
+	if d.LogBodies {
+		trace := map[string]any{
+			"http_method":          req.Method,
+			"http_url":             req.URL.String(),
+			"http_request_headers": redactHeaders(req.Header),
+		}
+		if req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				buf, _ := io.ReadAll(io.LimitReader(body, maxLoggedBodyLength))
+				body.Close()
+				trace["http_request_body"] = redactBody(buf, req.Header.Get("Content-Type"))
+			}
+		}
+		tflog.SubsystemTrace(ctx, LogSubsystem, "Sending NSX API request", trace)
+	}
+
+	start := time.Now()
+	rsp, err := d.Doer.Do(req)
+	fields["http_duration_ms"] = time.Since(start).Milliseconds()
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, LogSubsystem, "NSX API request failed", fields)
+		return rsp, err
+	}
+	fields["http_status"] = rsp.StatusCode
+	tflog.SubsystemDebug(ctx, LogSubsystem, "NSX API request", fields)
+
+	if d.LogBodies {
+		body, err := io.ReadAll(rsp.Body)
+		rsp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		rsp.Body = io.NopCloser(bytes.NewReader(body))
+
+		if len(body) > maxLoggedBodyLength {
+			body = body[:maxLoggedBodyLength]
+		}
+		tflog.SubsystemTrace(ctx, LogSubsystem, "Received NSX API response", map[string]any{
+			"http_method":           req.Method,
+			"http_url":              req.URL.String(),
+			"http_status":           rsp.StatusCode,
+			"http_response_headers": redactHeaders(rsp.Header),
+			"http_response_body":    redactBody(body, rsp.Header.Get("Content-Type")),
+		})
+	}
+	return rsp, nil
+}
+
+// redactHeaders returns the headers with credentials and session cookies
+// replaced.
+func redactHeaders(header http.Header) map[string]string {
+	out := make(map[string]string, len(header))
+	for name, values := range header {
+		out[name] = strings.Join(values, ", ")
+	}
+	for _, name := range sensitiveHeaders {
+		if _, ok := out[http.CanonicalHeaderKey(name)]; ok {
+			out[http.CanonicalHeaderKey(name)] = redacted
+		}
+	}
+	if cookies := header.Values("Cookie"); len(cookies) > 0 {
+		out["Cookie"] = redactCookies(strings.Join(cookies, "; "))
+	}
+	if cookies := header.Values("Set-Cookie"); len(cookies) > 0 {
+		redactedCookies := make([]string, 0, len(cookies))
+		for _, cookie := range cookies {
+			redactedCookies = append(redactedCookies, redactCookies(cookie))
+		}
+		out["Set-Cookie"] = strings.Join(redactedCookies, ", ")
+	}
+	return out
+}
+
+// redactCookies replaces the value of the NSX session cookie in a Cookie or
+// Set-Cookie header.
+func redactCookies(cookies string) string {
+	parts := strings.Split(cookies, ";")
+	for i, part := range parts {
+		name, _, ok := strings.Cut(part, "=")
+		if ok && strings.TrimSpace(name) == sessionCookieName {
+			parts[i] = name + "=" + redacted
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// redactBody returns a request or response body with the values of
+// sensitive fields replaced. Bodies which can't be parsed are logged as is.
+func redactBody(body []byte, contentType string) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return redacted
+		}
+		for name := range values {
+			if sensitiveField.MatchString(name) {
+				values.Set(name, redacted)
+			}
+		}
+		return values.Encode()
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+	buf, err := json.Marshal(redactJSON(v))
+	if err != nil {
+		return redacted
+	}
+	return string(buf)
+}
+
+func redactJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if sensitiveField.MatchString(key) {
+				v[key] = redacted
+			} else {
+				v[key] = redactJSON(value)
+			}
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = redactJSON(value)
+		}
+	}
+	return v
+}
@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"strings"
+)
+
+// MergeObject returns the body of a PUT replacing current, an object as read
+// from NSX, with obj. Each field obj's type models is taken from obj, or
+// removed if obj leaves it out, while fields it doesn't model are kept from
+// current, so that the PUT doesn't reset settings this client doesn't know
+// about. It returns obj itself if current is nil.
+func MergeObject(current map[string]any, obj any) (any, error) {
+	if current == nil {
+		return obj, nil
+	}
+
+	buf, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(buf, &fields); err != nil {
+		return nil, err
+	}
+
+	merged := maps.Clone(current)
+	t := reflect.TypeOf(obj)
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			delete(merged, name)
+		}
+	}
+	maps.Copy(merged, fields)
+	return merged, nil
+}
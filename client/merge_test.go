@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeObject(t *testing.T) {
+	revision := int64(3)
+	current := map[string]any{
+		"id":               "web",
+		"display_name":     "old",
+		"description":      "removed",
+		"replication_mode": "SOURCE",
+		"advanced_config":  map[string]any{"connectivity": "ON"},
+		"_revision":        float64(4),
+	}
+	merged, err := MergeObject(current, Segment{Id: "web", DisplayName: "new", ResourceType: "Segment", Revision: &revision})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]any{
+		"id":               "web",
+		"display_name":     "new",
+		"resource_type":    "Segment",
+		"replication_mode": "SOURCE",
+		"advanced_config":  map[string]any{"connectivity": "ON"},
+		"_revision":        float64(3),
+	}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("expected %v, got %v", expected, merged)
+	}
+	if current["display_name"] != "old" {
+		t.Error("expected current not to be modified")
+	}
+
+	segment := Segment{Id: "web"}
+	if merged, _ := MergeObject(nil, segment); !reflect.DeepEqual(merged, segment) {
+		t.Errorf("expected the object itself without a current object, got %v", merged)
+	}
+}
@@ -6,11 +6,16 @@ package client
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 )
 
 type ListSegmentPortsRequest struct {
@@ -18,39 +23,104 @@ type ListSegmentPortsRequest struct {
 }
 
 type ListSegmentPortsResponse struct {
+	Cursor      string        `json:"cursor"`
 	ResultCount int           `json:"result_count"`
 	Results     []SegmentPort `json:"results"`
 }
 
+// ListSegmentPortsParams defines the optional query parameters for
+// ListSegmentPorts.
+type ListSegmentPortsParams struct {
+	// Cursor is the opaque cursor returned with the previous page.
+	Cursor string
+	// PageSize is the maximum number of results per page. NSX defaults to 1000.
+	PageSize int
+	// SortBy is the field to sort results on.
+	SortBy        string
+	SortAscending *bool
+}
+
 type PatchSegmentPortRequest struct {
 	SegmentId   string      `json:"segment_id"`
 	PortId      string      `json:"port_id"`
 	SegmentPort SegmentPort `json:"segment_port"`
 }
 
+type UpdateSegmentPortRequest struct {
+	SegmentId   string      `json:"segment_id"`
+	PortId      string      `json:"port_id"`
+	SegmentPort SegmentPort `json:"segment_port"`
+	// Current is the port as read from NSX, if set, which SegmentPort is
+	// merged onto with MergeObject.
+	Current map[string]any `json:"-"`
+}
+
 type PortAddressBindingEntry struct {
 	IpAddress  string `json:"ip_address"`
 	MacAddress string `json:"mac_address"`
-	VlanId     string `json:"vlan_id"`
+	VlanId     int64  `json:"vlan_id,omitempty"`
 }
 
 type PortAttachment struct {
-	AllocateAddresses string `json:"allocate_addresses"`
-	AppId             string `json:"app_id"`
-	ContextId         string `json:"context_id"`
-	Id                string `json:"id"`
-	TrafficTag        string `json:"traffic_tag"`
-	Type              string `json:"type"`
+	AllocateAddresses string `json:"allocate_addresses,omitempty"`
+	AppId             string `json:"app_id,omitempty"`
+	ContextId         string `json:"context_id,omitempty"`
+	// ContextType is the type of the parent of a CHILD attachment, such as
+	// PARENTVIF or RESTORE_VIF.
+	ContextType string `json:"context_type,omitempty"`
+	// EvpnVlans are the VLAN ranges of an EVPN tenant, such as 100-200.
+	EvpnVlans []string `json:"evpn_vlans,omitempty"`
+	// HyperbusMode is ENABLE or DISABLE.
+	HyperbusMode string `json:"hyperbus_mode,omitempty"`
+	Id           string `json:"id,omitempty"`
+	TrafficTag   int64  `json:"traffic_tag,omitempty"`
+	Type         string `json:"type,omitempty"`
+}
+
+// Tag is an NSX scope and tag pair. The scope may be empty.
+type Tag struct {
+	Scope string `json:"scope,omitempty"`
+	Tag   string `json:"tag"`
+}
+
+// SegmentExtraConfig is a vendor specific setting passed through to the
+// port's hypervisor.
+type SegmentExtraConfig struct {
+	ConfigPair UnboundedKeyValuePair `json:"config_pair"`
+}
+
+type UnboundedKeyValuePair struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
 }
 
 type SegmentPort struct {
-	AddressBindings PortAddressBindingEntry `json:"address_bindings"`
-	AdminState      string                  `json:"admin_state"`
-	Attachment      PortAttachment          `json:"attachment"`
-	Description     string                  `json:"description"`
-	DisplayName     string                  `json:"display_name"`
-	Id              string                  `json:"id"`
-	ResourceType    string                  `json:"resource_type"`
+	AddressBindings []PortAddressBindingEntry `json:"address_bindings,omitempty"`
+	AdminState      string                    `json:"admin_state"`
+	Attachment      *PortAttachment           `json:"attachment,omitempty"`
+	Description     string                    `json:"description"`
+	DisplayName     string                    `json:"display_name"`
+	ExtraConfigs    []SegmentExtraConfig      `json:"extra_configs,omitempty"`
+	Id              string                    `json:"id"`
+	// IgnoredAddressBindings are addresses which NSX discovers on the port
+	// but must not bind to it.
+	IgnoredAddressBindings []PortAddressBindingEntry `json:"ignored_address_bindings,omitempty"`
+	// InitState is UNBLOCKED_VLAN or RESTORE_VIF.
+	InitState    string `json:"init_state,omitempty"`
+	ResourceType string `json:"resource_type"`
+	Tags         []Tag  `json:"tags,omitempty"`
+
+	// Path, ParentPath, UniqueId and RealizationId are set by NSX.
+	Path          string `json:"path,omitempty"`
+	ParentPath    string `json:"parent_path,omitempty"`
+	UniqueId      string `json:"unique_id,omitempty"`
+	RealizationId string `json:"realization_id,omitempty"`
+
+	// Revision must match the current revision when updating a port, so
+	// that NSX can reject writes based on stale data.
+	Revision         *int64 `json:"_revision,omitempty"`
+	LastModifiedUser string `json:"_last_modified_user,omitempty"`
+	LastModifiedTime int64  `json:"_last_modified_time,omitempty"`
 }
 
 // RequestEditorFn  is the function signature for the RequestEditor callback function.
@@ -73,6 +143,23 @@ type Client struct {
 	Client HttpRequestDoer
 
 	RequestEditors []RequestEditorFn
+
+	// Session authenticates every request when a username is provided.
+	Session *SessionAuthenticator
+
+	// tlsConfig, proxy and timeout are used to build the default Doer.
+	tlsConfig *tls.Config
+	proxy     func(*http.Request) (*url.URL, error)
+	timeout   time.Duration
+
+	retryPolicy *RetryPolicy
+
+	// failoverNodes are the NSX Manager nodes requests fail over to when
+	// Server can't be reached.
+	failoverNodes []string
+
+	// PolicyContext is the tenancy that requests are made in.
+	PolicyContext PolicyContext
 }
 
 type ClientOption func(*Client) error
@@ -83,6 +170,9 @@ func NewClient(server string, username string, password string, opts ...ClientOp
 		Server:   server,
 		Username: username,
 		Password: password,
+
+		tlsConfig: &tls.Config{},
+		proxy:     http.ProxyFromEnvironment,
 	}
 	// mutate client and add all optional params
 	for _, o := range opts {
@@ -90,9 +180,40 @@ func NewClient(server string, username string, password string, opts ...ClientOp
 			return nil, err
 		}
 	}
+	// the pin skips chain verification, so CA certificates would be ignored
+	if client.tlsConfig.RootCAs != nil && client.tlsConfig.VerifyConnection != nil {
+		return nil, errors.New("a pinned certificate fingerprint can't be combined with CA certificates, which it skips verification against")
+	}
+	if client.tlsConfig.RootCAs != nil && client.tlsConfig.InsecureSkipVerify {
+		return nil, errors.New("insecure connections can't be combined with CA certificates, which they skip verification against")
+	}
 	// create httpClient, if not already present
 	if client.Client == nil {
-		client.Client = &http.Client{}
+		client.Client = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: client.tlsConfig,
+				Proxy:           client.proxy,
+			},
+			Timeout: client.timeout,
+		}
+	}
+	// log each request as sent, beneath failover and retries
+	client.Client = NewLoggingDoer(client.Client)
+	if len(client.failoverNodes) > 0 {
+		failover, err := NewFailoverDoer(client.Client, append([]string{client.Server}, client.failoverNodes...)...)
+		if err != nil {
+			return nil, err
+		}
+		client.Client = failover
+	}
+	if client.retryPolicy != nil {
+		client.Client = &RetryDoer{Doer: client.Client, Policy: *client.retryPolicy}
+	}
+	// authenticate with a session rather than per-request basic auth, unless
+	// a client certificate already identifies us
+	if client.Username != "" && client.Session == nil && len(client.tlsConfig.Certificates) == 0 {
+		client.Session = NewSessionAuthenticator(client.Server, client.Username, client.Password, client.Client)
+		client.RequestEditors = append([]RequestEditorFn{client.Session.Intercept}, client.RequestEditors...)
 	}
 	return &client, nil
 }
@@ -106,6 +227,116 @@ func WithHTTPClient(doer HttpRequestDoer) ClientOption {
 	}
 }
 
+// WithInsecure disables verification of the NSX Manager's certificate. It has
+// no effect when combined with WithHTTPClient.
+func WithInsecure(insecure bool) ClientOption {
+	return func(c *Client) error {
+		c.tlsConfig.InsecureSkipVerify = insecure
+		return nil
+	}
+}
+
+// WithClientCertificate authenticates with a certificate, such as an NSX
+// principal identity, instead of a username and password. It has no effect
+// when combined with WithHTTPClient.
+func WithClientCertificate(cert tls.Certificate) ClientOption {
+	return func(c *Client) error {
+		c.tlsConfig.Certificates = append(c.tlsConfig.Certificates, cert)
+		return nil
+	}
+}
+
+// WithCACertificates verifies the NSX Manager's certificate against the PEM
+// encoded CA certificates instead of the system roots. It has no effect when
+// combined with WithHTTPClient.
+func WithCACertificates(pem []byte) ClientOption {
+	return func(c *Client) error {
+		pool, err := certPool(pem)
+		if err != nil {
+			return err
+		}
+		c.tlsConfig.RootCAs = pool
+		return nil
+	}
+}
+
+// WithCertificateFingerprint pins the NSX Manager's certificate to the one
+// with the SHA-256 fingerprint, as parsed by ParseCertificateFingerprint. A
+// certificate with that fingerprint is trusted even if it is self-signed or
+// not signed by a trusted CA, and any other certificate is rejected. It
+// can't be combined with WithCACertificates, and has no effect when
+// combined with WithHTTPClient.
+func WithCertificateFingerprint(fingerprint string) ClientOption {
+	return func(c *Client) error {
+		sum, err := ParseCertificateFingerprint(fingerprint)
+		if err != nil {
+			return err
+		}
+		// the pin replaces chain verification, which would reject the
+		// self-signed certificates NSX Managers are installed with
+		c.tlsConfig.InsecureSkipVerify = true
+		c.tlsConfig.VerifyConnection = verifyFingerprint(sum)
+		return nil
+	}
+}
+
+// WithProxy sends requests through the HTTP proxy at proxyURL, except to
+// hosts listed in the NO_PROXY environment variable. Without it, the proxy
+// is taken from the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment
+// variables. It has no effect when combined with WithHTTPClient.
+func WithProxy(proxyURL string) ClientOption {
+	return func(c *Client) error {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid proxy URL %q: expected a URL such as http://proxy.example.com:3128", proxyURL)
+		}
+		c.proxy = proxyFunc(u)
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout of the default Doer.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.timeout = timeout
+		return nil
+	}
+}
+
+// WithRetryPolicy retries requests which NSX rejects because it is busy,
+// wrapping whichever Doer the client uses.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		c.retryPolicy = &policy
+		return nil
+	}
+}
+
+// WithFailoverNodes adds NSX Manager nodes which requests fail over to when
+// the server, and any nodes before them, can't be reached. Each node is a
+// URL such as https://nsx-02.example.com. It wraps whichever Doer the client
+// uses.
+func WithFailoverNodes(nodes ...string) ClientOption {
+	return func(c *Client) error {
+		c.failoverNodes = append(c.failoverNodes, nodes...)
+		return nil
+	}
+}
+
+// WithPolicyContext sets the tenancy that requests are made in.
+func WithPolicyContext(pc PolicyContext) ClientOption {
+	return func(c *Client) error {
+		if err := pc.Validate(); err != nil {
+			return err
+		}
+		c.PolicyContext = pc.normalized()
+		return nil
+	}
+}
+
 // WithRequestEditorFn allows setting up a callback function, which will be
 // called right before sending the request. This can be used to mutate the request.
 func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
@@ -129,6 +360,58 @@ func (c *Client) applyEditors(ctx context.Context, req *http.Request, additional
 	return nil
 }
 
+// do sends the request, replaying it once with a fresh session if NSX
+// rejects the current one.
+func (c *Client) do(ctx context.Context, req *http.Request, reqEditors []RequestEditorFn) (*http.Response, error) {
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	rsp, err := c.Client.Do(req)
+	if err != nil || c.Session == nil {
+		return rsp, err
+	}
+	if rsp.StatusCode != http.StatusUnauthorized && rsp.StatusCode != http.StatusForbidden {
+		return rsp, nil
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return rsp, nil
+	}
+	rsp.Body.Close()
+
+	if err := c.Session.Renew(ctx, req); err != nil {
+		return nil, err
+	}
+
+	retry := req.Clone(ctx)
+	retry.Header.Del("Cookie")
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.Session.Intercept(ctx, retry); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(retry)
+}
+
+// CloseSession destroys the NSX session, if one was created.
+func (c *Client) CloseSession(ctx context.Context) error {
+	if c.Session == nil {
+		return nil
+	}
+	return c.Session.Destroy(ctx)
+}
+
+// InContext returns a copy of the client which makes requests in pc. The
+// copy shares the session and transport of c.
+func (c *Client) InContext(pc PolicyContext) *Client {
+	scoped := *c
+	scoped.PolicyContext = pc.normalized()
+	return &scoped
+}
+
 type ClientInterface interface {
 	DeleteSegmentPort(string) (*http.Response, error)
 	ListSegmentPorts(string) (*ListSegmentPortsResponse, error)
@@ -137,18 +420,14 @@ type ClientInterface interface {
 }
 
 func (c *Client) DeleteSegmentPort(ctx context.Context, segment_id string, port_id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewDeleteSegmentPortRequest(c.Server, segment_id, port_id)
+	req, err := NewDeleteSegmentPortRequest(c.Server, c.PolicyContext, segment_id, port_id)
 	if err != nil {
 		return nil, err
 	}
-	req = req.WithContext(ctx)
-	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
-		return nil, err
-	}
-	return c.Client.Do(req)
+	return c.do(ctx, req, reqEditors)
 }
 
-func NewDeleteSegmentPortRequest(server string, segment_id string, port_id string) (*http.Request, error) {
+func NewDeleteSegmentPortRequest(server string, pc PolicyContext, segment_id string, port_id string) (*http.Request, error) {
 	var err error
 
 	serverURL, err := url.Parse(server)
@@ -156,7 +435,7 @@ func NewDeleteSegmentPortRequest(server string, segment_id string, port_id strin
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/policy/api/v1/infra/segments/%s/ports/%s", segment_id, port_id)
+	operationPath := pc.APIPath() + pc.SegmentPortPath(segment_id, port_id)
 	queryURL, err := serverURL.Parse(operationPath)
 	if err != nil {
 		return nil, err
@@ -170,20 +449,15 @@ func NewDeleteSegmentPortRequest(server string, segment_id string, port_id strin
 	return req, nil
 }
 
-func (c *Client) ListSegmentPorts(ctx context.Context, segment_id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewListSegmentPortsRequest(c.Server, c.Username, c.Password, segment_id)
+func (c *Client) ListSegmentPorts(ctx context.Context, segment_id string, params *ListSegmentPortsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListSegmentPortsRequest(c.Server, c.PolicyContext, segment_id, params)
 	if err != nil {
 		return nil, err
 	}
-
-	req = req.WithContext(ctx)
-	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
-		return nil, err
-	}
-	return c.Client.Do(req)
+	return c.do(ctx, req, reqEditors)
 }
 
-func NewListSegmentPortsRequest(server string, user string, pass string, segment_id string) (*http.Request, error) {
+func NewListSegmentPortsRequest(server string, pc PolicyContext, segment_id string, params *ListSegmentPortsParams) (*http.Request, error) {
 	var err error
 
 	serverURL, err := url.Parse(server)
@@ -191,37 +465,73 @@ func NewListSegmentPortsRequest(server string, user string, pass string, segment
 		return nil, err
 	}
 
-	operationPath := "/policy/api/v1/infra/segments/" + segment_id + "/ports"
+	operationPath := pc.APIPath() + pc.SegmentPath(segment_id) + "/ports"
 	queryURL, err := serverURL.Parse(operationPath)
 	if err != nil {
 		return nil, err
 	}
 
+	if params != nil {
+		queryValues := queryURL.Query()
+		if params.Cursor != "" {
+			queryValues.Set("cursor", params.Cursor)
+		}
+		if params.PageSize > 0 {
+			queryValues.Set("page_size", strconv.Itoa(params.PageSize))
+		}
+		if params.SortBy != "" {
+			queryValues.Set("sort_by", params.SortBy)
+		}
+		if params.SortAscending != nil {
+			queryValues.Set("sort_ascending", strconv.FormatBool(*params.SortAscending))
+		}
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
 	req, err := http.NewRequest(http.MethodGet, queryURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.SetBasicAuth(user, pass)
-
 	return req, nil
 }
 
-func (c *Client) GetSegmentPort(ctx context.Context, segment_id string, port_id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewGetSegmentPortRequest(c.Server, c.Username, c.Password, segment_id, port_id)
+// SegmentPorts iterates over every port of a segment, following the cursor
+// from page to page. Iteration stops after the first error.
+func (c *Client) SegmentPorts(ctx context.Context, segment_id string, params *ListSegmentPortsParams, reqEditors ...RequestEditorFn) iter.Seq2[SegmentPort, error] {
+	return paginate[SegmentPort](func(cursor string) (*http.Response, error) {
+		var page ListSegmentPortsParams
+		if params != nil {
+			page = *params
+		}
+		if cursor != "" {
+			page.Cursor = cursor
+		}
+		return c.ListSegmentPorts(ctx, segment_id, &page, reqEditors...)
+	}, "segment ports")
+}
 
-	if err != nil {
-		return nil, err
+// ListAllSegmentPorts returns every port of a segment, reading all pages.
+func (c *Client) ListAllSegmentPorts(ctx context.Context, segment_id string, params *ListSegmentPortsParams, reqEditors ...RequestEditorFn) ([]SegmentPort, error) {
+	var ports []SegmentPort
+	for port, err := range c.SegmentPorts(ctx, segment_id, params, reqEditors...) {
+		if err != nil {
+			return nil, err
+		}
+		ports = append(ports, port)
 	}
+	return ports, nil
+}
 
-	req = req.WithContext(ctx)
-	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+func (c *Client) GetSegmentPort(ctx context.Context, segment_id string, port_id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetSegmentPortRequest(c.Server, c.PolicyContext, segment_id, port_id)
+	if err != nil {
 		return nil, err
 	}
-	return c.Client.Do(req)
+	return c.do(ctx, req, reqEditors)
 }
 
-func NewGetSegmentPortRequest(server string, user string, pass string, segment_id string, port_id string) (*http.Request, error) {
+func NewGetSegmentPortRequest(server string, pc PolicyContext, segment_id string, port_id string) (*http.Request, error) {
 	var err error
 
 	serverURL, err := url.Parse(server)
@@ -229,7 +539,7 @@ func NewGetSegmentPortRequest(server string, user string, pass string, segment_i
 		return nil, err
 	}
 
-	operationPath := "/policy/api/v1/infra/segments/" + segment_id + "/ports/" + port_id
+	operationPath := pc.APIPath() + pc.SegmentPortPath(segment_id, port_id)
 	queryURL, err := serverURL.Parse(operationPath)
 	if err != nil {
 		return nil, err
@@ -240,25 +550,18 @@ func NewGetSegmentPortRequest(server string, user string, pass string, segment_i
 		return nil, err
 	}
 
-	req.SetBasicAuth(user, pass)
-
 	return req, nil
 }
 
 func (c *Client) PatchSegmentPort(ctx context.Context, body PatchSegmentPortRequest, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewPatchSegmentPortRequest(c.Server, c.Username, c.Password, body)
+	req, err := NewPatchSegmentPortRequest(c.Server, c.PolicyContext, body)
 	if err != nil {
 		return nil, err
 	}
-
-	req = req.WithContext(ctx)
-	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
-		return nil, err
-	}
-	return c.Client.Do(req)
+	return c.do(ctx, req, reqEditors)
 }
 
-func NewPatchSegmentPortRequest(server string, user string, pass string, body PatchSegmentPortRequest) (*http.Request, error) {
+func NewPatchSegmentPortRequest(server string, pc PolicyContext, body PatchSegmentPortRequest) (*http.Request, error) {
 	var err error
 
 	serverURL, err := url.Parse(server)
@@ -266,7 +569,7 @@ func NewPatchSegmentPortRequest(server string, user string, pass string, body Pa
 		return nil, err
 	}
 
-	operationPath := "/policy/api/v1/infra/segments/" + body.SegmentId + "/ports/" + body.PortId
+	operationPath := pc.APIPath() + pc.SegmentPortPath(body.SegmentId, body.PortId)
 	queryURL, err := serverURL.Parse(operationPath)
 	if err != nil {
 		return nil, err
@@ -284,8 +587,26 @@ func NewPatchSegmentPortRequest(server string, user string, pass string, body Pa
 		return nil, err
 	}
 
-	req.SetBasicAuth(user, pass)
 	req.Header.Add("Content-Type", "application/json")
 
 	return req, nil
 }
+
+// UpdateSegmentPort replaces a segment port. Unlike PatchSegmentPort, NSX
+// rejects the request with 412 Precondition Failed if the port's revision
+// doesn't match body.SegmentPort.Revision.
+func (c *Client) UpdateSegmentPort(ctx context.Context, body UpdateSegmentPortRequest, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateSegmentPortRequest(c.Server, c.PolicyContext, body)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewUpdateSegmentPortRequest(server string, pc PolicyContext, body UpdateSegmentPortRequest) (*http.Request, error) {
+	obj, err := MergeObject(body.Current, body.SegmentPort)
+	if err != nil {
+		return nil, err
+	}
+	return newPolicyObjectRequest(server, pc, http.MethodPut, pc.SegmentPortPath(body.SegmentId, body.PortId), obj)
+}
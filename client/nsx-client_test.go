@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestListAllSegmentPorts(t *testing.T) {
+	const total = 7
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/policy/api/v1/infra/segments/seg/ports" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("sort_by") != "display_name" {
+			t.Errorf("expected sort_by to be sent on every page")
+		}
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+		start, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+
+		body := ListSegmentPortsResponse{ResultCount: total}
+		for i := start; i < min(start+pageSize, total); i++ {
+			body.Results = append(body.Results, SegmentPort{Id: fmt.Sprintf("port-%d", i)})
+		}
+		if start+pageSize < total {
+			body.Cursor = strconv.Itoa(start + pageSize)
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ports, err := c.ListAllSegmentPorts(context.Background(), "seg", &ListSegmentPortsParams{PageSize: 3, SortBy: "display_name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ports) != total {
+		t.Fatalf("expected %d ports, got %d", total, len(ports))
+	}
+	for i, port := range ports {
+		if port.Id != fmt.Sprintf("port-%d", i) {
+			t.Fatalf("unexpected port %d: %s", i, port.Id)
+		}
+	}
+
+	// Stopping iteration early doesn't fetch further pages.
+	count := 0
+	for _, err := range c.SegmentPorts(context.Background(), "seg", &ListSegmentPortsParams{PageSize: 3, SortBy: "display_name"}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count++; count == 2 {
+			break
+		}
+	}
+}
+
+func TestListAllSegmentPortsError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"httpStatus": "NOT_FOUND", "error_code": 500090, "error_message": "Segment seg not found"}`)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.ListAllSegmentPorts(context.Background(), "seg", nil)
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected a 404 APIError, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+)
+
+// paginate iterates over the results of an NSX list API, calling list with
+// the cursor of each page in turn, starting with an empty one, until NSX
+// returns no further cursor. kind names the results in decoding errors.
+// Iteration stops after the first error.
+func paginate[T any](list func(cursor string) (*http.Response, error), kind string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		var cursor string
+		for {
+			rsp, err := list(cursor)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if rsp.StatusCode != http.StatusOK {
+				yield(zero, ParseAPIError(rsp))
+				return
+			}
+
+			var body struct {
+				Cursor  string `json:"cursor"`
+				Results []T    `json:"results"`
+			}
+			err = json.NewDecoder(rsp.Body).Decode(&body)
+			rsp.Body.Close()
+			if err != nil {
+				yield(zero, fmt.Errorf("invalid format received for %s: %w", kind, err))
+				return
+			}
+
+			for _, result := range body.Results {
+				if !yield(result, nil) {
+					return
+				}
+			}
+			if body.Cursor == "" || body.Cursor == cursor || len(body.Results) == 0 {
+				return
+			}
+			cursor = body.Cursor
+		}
+	}
+}
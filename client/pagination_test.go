@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	page := func(body string) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	}
+
+	// NSX returning the cursor it was sent ends iteration rather than
+	// requesting the same page forever.
+	var cursors []string
+	var ids []string
+	for id, err := range paginate[string](func(cursor string) (*http.Response, error) {
+		cursors = append(cursors, cursor)
+		if cursor == "" {
+			return page(`{"cursor": "2", "results": ["a", "b"]}`)
+		}
+		return page(`{"cursor": "2", "results": ["c"]}`)
+	}, "ids") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if !slices.Equal(cursors, []string{"", "2"}) {
+		t.Errorf("unexpected cursors %q", cursors)
+	}
+	if !slices.Equal(ids, []string{"a", "b", "c"}) {
+		t.Errorf("unexpected results %q", ids)
+	}
+
+	listErr := errors.New("connection refused")
+	for _, err := range paginate[string](func(string) (*http.Response, error) { return nil, listErr }, "ids") {
+		if !errors.Is(err, listErr) {
+			t.Errorf("expected the list error, got %v", err)
+		}
+	}
+
+	for _, err := range paginate[string](func(string) (*http.Response, error) { return page(`{"results": [1]}`) }, "ids") {
+		if err == nil || !strings.Contains(err.Error(), "invalid format received for ids") {
+			t.Errorf("expected a decoding error, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Consolidated realization states reported by NSX. Any other state means
+// realization is still in progress.
+const (
+	RealizationStateSuccess = "SUCCESS"
+	RealizationStateError   = "ERROR"
+)
+
+// ConsolidatedRealizedStatus is the realization status of an intent across
+// every enforcement point.
+type ConsolidatedRealizedStatus struct {
+	IntentPath         string             `json:"intent_path"`
+	PublishStatus      string             `json:"publish_status"`
+	ConsolidatedStatus ConsolidatedStatus `json:"consolidated_status"`
+}
+
+type ConsolidatedStatus struct {
+	ConsolidatedStatus string `json:"consolidated_status"`
+}
+
+type ListRealizedEntitiesResponse struct {
+	ResultCount int              `json:"result_count"`
+	Results     []RealizedEntity `json:"results"`
+}
+
+// RealizedEntity is an object NSX realized from an intent.
+type RealizedEntity struct {
+	Id            string          `json:"id"`
+	DisplayName   string          `json:"display_name"`
+	EntityType    string          `json:"entity_type"`
+	State         string          `json:"state"`
+	RuntimeStatus string          `json:"runtime_status"`
+	Alarms        []RealizedAlarm `json:"alarms,omitempty"`
+}
+
+type RealizedAlarm struct {
+	Message      string    `json:"message"`
+	ErrorDetails *APIError `json:"error_details,omitempty"`
+}
+
+// RealizationError is returned by WaitForRealization when NSX fails to
+// realize an intent.
+type RealizationError struct {
+	IntentPath string
+	// Messages are the alarms raised by the realized entities.
+	Messages []string
+}
+
+func (e *RealizationError) Error() string {
+	msg := fmt.Sprintf("NSX failed to realize %s", e.IntentPath)
+	if len(e.Messages) > 0 {
+		msg += ": " + strings.Join(e.Messages, "; ")
+	}
+	return msg
+}
+
+// GetRealizedStatus reads the consolidated realization status of the object
+// at intent_path, a policy path such as /infra/segments/web/ports/vm-1.
+func (c *Client) GetRealizedStatus(ctx context.Context, intent_path string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetRealizedStatusRequest(c.Server, c.PolicyContext, intent_path)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewGetRealizedStatusRequest(server string, pc PolicyContext, intent_path string) (*http.Request, error) {
+	return newRealizedStateRequest(server, pc, "/realized-state/status", intent_path)
+}
+
+// ListRealizedEntities lists the objects realized from the object at
+// intent_path, along with any alarms raised while realizing them.
+func (c *Client) ListRealizedEntities(ctx context.Context, intent_path string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListRealizedEntitiesRequest(c.Server, c.PolicyContext, intent_path)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewListRealizedEntitiesRequest(server string, pc PolicyContext, intent_path string) (*http.Request, error) {
+	return newRealizedStateRequest(server, pc, "/realized-state/realized-entities", intent_path)
+}
+
+func newRealizedStateRequest(server string, pc PolicyContext, operation string, intent_path string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := pc.APIPath() + pc.InfraPath() + operation
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	queryValues := queryURL.Query()
+	queryValues.Set("intent_path", intent_path)
+	queryURL.RawQuery = queryValues.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// WaitForRealization polls the realization status of the object at
+// intent_path every interval until NSX reports SUCCESS. A *RealizationError
+// is returned if NSX reports ERROR. Cancelling ctx, such as with a timeout,
+// abandons the wait.
+func (c *Client) WaitForRealization(ctx context.Context, intent_path string, interval time.Duration) error {
+	state := ""
+	for {
+		rsp, err := c.GetRealizedStatus(ctx, intent_path)
+		if err != nil {
+			return realizationTimeout(ctx, intent_path, state, err)
+		}
+		// NSX only knows the intent once it has been published
+		if rsp.StatusCode != http.StatusOK && rsp.StatusCode != http.StatusNotFound {
+			return ParseAPIError(rsp)
+		}
+		if rsp.StatusCode == http.StatusOK {
+			var status ConsolidatedRealizedStatus
+			err = json.NewDecoder(rsp.Body).Decode(&status)
+			rsp.Body.Close()
+			if err != nil {
+				return fmt.Errorf("invalid format received for realization status of %s: %w", intent_path, err)
+			}
+			state = status.ConsolidatedStatus.ConsolidatedStatus
+		} else {
+			rsp.Body.Close()
+		}
+
+		switch state {
+		case RealizationStateSuccess:
+			return nil
+		case RealizationStateError:
+			return c.realizationError(ctx, intent_path)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return realizationTimeout(ctx, intent_path, state, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+func realizationTimeout(ctx context.Context, intent_path string, state string, err error) error {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	if state == "" {
+		state = "unknown"
+	}
+	return fmt.Errorf("timed out waiting for NSX to realize %s, last realization state was %s: %w", intent_path, state, err)
+}
+
+// realizationError describes why intent_path failed to realize, using the
+// alarms of its realized entities.
+func (c *Client) realizationError(ctx context.Context, intent_path string) error {
+	realizationErr := &RealizationError{IntentPath: intent_path}
+
+	rsp, err := c.ListRealizedEntities(ctx, intent_path)
+	if err != nil {
+		return realizationErr
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return realizationErr
+	}
+
+	var entities ListRealizedEntitiesResponse
+	if err := json.NewDecoder(rsp.Body).Decode(&entities); err != nil {
+		return realizationErr
+	}
+	for _, entity := range entities.Results {
+		for _, alarm := range entity.Alarms {
+			msg := alarm.Message
+			if alarm.ErrorDetails != nil && alarm.ErrorDetails.ErrorMessage != "" {
+				msg = alarm.ErrorDetails.ErrorMessage
+			}
+			if msg != "" {
+				realizationErr.Messages = append(realizationErr.Messages, msg)
+			}
+		}
+	}
+	return realizationErr
+}
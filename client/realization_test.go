@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testIntentPath = "/infra/segments/seg/ports/port"
+
+// newRealizationServer reports the realization states in turn, repeating the
+// last one.
+func newRealizationServer(t *testing.T, states ...string) (*httptest.Server, *atomic.Int32) {
+	var polls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("intent_path") != testIntentPath {
+			t.Errorf("unexpected intent_path %q", r.URL.Query().Get("intent_path"))
+		}
+		switch r.URL.Path {
+		case "/policy/api/v1/infra/realized-state/status":
+			state := states[min(int(polls.Add(1)), len(states))-1]
+			fmt.Fprintf(w, `{"intent_path": %q, "consolidated_status": {"consolidated_status": %q}}`, testIntentPath, state)
+		case "/policy/api/v1/infra/realized-state/realized-entities":
+			fmt.Fprint(w, `{"results": [{"id": "port", "state": "ERROR", "alarms": [{"message": "VIF not found"}]}]}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &polls
+}
+
+func TestWaitForRealization(t *testing.T) {
+	ts, polls := newRealizationServer(t, "UNINITIALIZED", "IN_PROGRESS", "SUCCESS")
+	c, err := NewClient(ts.URL, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.WaitForRealization(context.Background(), testIntentPath, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if polls.Load() != 3 {
+		t.Fatalf("expected 3 polls, got %d", polls.Load())
+	}
+}
+
+func TestWaitForRealizationError(t *testing.T) {
+	ts, _ := newRealizationServer(t, "IN_PROGRESS", "ERROR")
+	c, err := NewClient(ts.URL, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.WaitForRealization(context.Background(), testIntentPath, time.Millisecond)
+	var realizationErr *RealizationError
+	if !errors.As(err, &realizationErr) {
+		t.Fatalf("expected a RealizationError, got %v", err)
+	}
+	if len(realizationErr.Messages) != 1 || realizationErr.Messages[0] != "VIF not found" {
+		t.Fatalf("expected the alarm message, got %q", realizationErr.Messages)
+	}
+}
+
+func TestWaitForRealizationTimeout(t *testing.T) {
+	ts, _ := newRealizationServer(t, "IN_PROGRESS")
+	c, err := NewClient(ts.URL, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err = c.WaitForRealization(ctx, testIntentPath, time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "IN_PROGRESS") {
+		t.Fatalf("expected the last realization state in %q", err.Error())
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests rejected by a busy NSX Manager are
+// retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of times a request is retried after the
+	// first attempt. Zero disables retries.
+	MaxRetries int
+
+	// MinDelay is the delay before the first retry. It doubles on every
+	// subsequent retry.
+	MinDelay time.Duration
+
+	// MaxDelay caps the delay between retries, including delays requested
+	// by a Retry-After header.
+	MaxDelay time.Duration
+
+	// RetryableStatusCodes are the HTTP status codes which cause a retry.
+	RetryableStatusCodes []int
+
+	// RetryableConflictCodes are the NSX error codes which cause a 409
+	// Conflict to be retried, even though 409 isn't one of the
+	// RetryableStatusCodes.
+	RetryableConflictCodes []int
+}
+
+// DefaultRetryPolicy retries the errors NSX returns while it is under load:
+// 429 Too Many Requests, 503 Service Unavailable, and 409 Conflict when the
+// object is busy. Other conflicts aren't retried, as retrying can't resolve
+// them, such as deleting a port which still has a VIF attached.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 4,
+		MinDelay:   500 * time.Millisecond,
+		MaxDelay:   30 * time.Second,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusServiceUnavailable,
+		},
+		RetryableConflictCodes: []int{ErrorCodeObjectBusy},
+	}
+}
+
+// retryable reports whether a response should be retried. The body of a
+// 409 Conflict is read to find its error code, and replaced so that the
+// caller can still read it.
+func (p RetryPolicy) retryable(rsp *http.Response) bool {
+	if slices.Contains(p.RetryableStatusCodes, rsp.StatusCode) {
+		return true
+	}
+	if rsp.StatusCode != http.StatusConflict || len(p.RetryableConflictCodes) == 0 {
+		return false
+	}
+
+	body, err := io.ReadAll(rsp.Body)
+	rsp.Body.Close()
+	rsp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	var apiErr APIError
+	return json.Unmarshal(body, &apiErr) == nil && slices.Contains(p.RetryableConflictCodes, apiErr.ErrorCode)
+}
+
+// delay returns how long to wait before the given retry, preferring the
+// server's Retry-After header when one is present.
+func (p RetryPolicy) delay(retry int, rsp *http.Response) time.Duration {
+	if d, ok := retryAfter(rsp); ok {
+		return min(d, p.MaxDelay)
+	}
+
+	d := p.MaxDelay
+	if retry < 32 && p.MinDelay<<retry < p.MaxDelay {
+		d = p.MinDelay << retry
+	}
+	// add jitter so that parallel operations don't retry in lockstep
+	if d > 1 {
+		d = d/2 + rand.N(d/2)
+	}
+	return d
+}
+
+func retryAfter(rsp *http.Response) (time.Duration, bool) {
+	v := rsp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// RetryDoer wraps an HttpRequestDoer, retrying requests which fail with one
+// of the policy's retryable status or conflict codes. Waiting between retries is
+// abandoned as soon as the request's context is cancelled.
+type RetryDoer struct {
+	Doer   HttpRequestDoer
+	Policy RetryPolicy
+}
+
+func (d *RetryDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for retry := 0; ; retry++ {
+		rsp, err := d.Doer.Do(req)
+		if err != nil || retry >= d.Policy.MaxRetries || !d.Policy.retryable(rsp) {
+			return rsp, err
+		}
+		// a request body which cannot be rewound cannot be replayed
+		if req.Body != nil && req.GetBody == nil {
+			return rsp, nil
+		}
+
+		delay := d.Policy.delay(retry, rsp)
+		_, _ = io.Copy(io.Discard, rsp.Body)
+		rsp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		next := req.Clone(ctx)
+		if req.GetBody != nil {
+			if next.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+		req = next
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	var attempts atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if len(body) == 0 {
+			t.Error("expected the request body to be replayed")
+		}
+		switch attempts.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.MinDelay = time.Millisecond
+	c, err := NewClient(ts.URL, "", "", WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsp, err := c.PatchSegmentPort(context.Background(), PatchSegmentPortRequest{SegmentId: "seg", PortId: "port"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rsp.StatusCode)
+	}
+	if attempts.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts.Load())
+	}
+}
+
+func TestRetryPolicyGivesUp(t *testing.T) {
+	var attempts atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.MaxRetries = 2
+	policy.MinDelay = time.Millisecond
+	c, err := NewClient(ts.URL, "", "", WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsp, err := c.GetSegmentPort(context.Background(), "seg", "port")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rsp.StatusCode)
+	}
+	if attempts.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts.Load())
+	}
+}
+
+func TestRetryPolicyConflict(t *testing.T) {
+	var attempts atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"error_code": 503040, "error_message": "Port has a VIF attached."}`))
+	}))
+	defer ts.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.MinDelay = time.Millisecond
+	c, err := NewClient(ts.URL, "", "", WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsp, err := c.DeleteSegmentPort(context.Background(), "seg", "port")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if attempts.Load() != 1 {
+		t.Fatalf("expected a conflict not to be retried, got %d attempts", attempts.Load())
+	}
+}
+
+func TestRetryPolicyObjectBusy(t *testing.T) {
+	var attempts atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		if attempts.Add(1) < 3 {
+			_, _ = w.Write([]byte(`{"error_code": 500105, "error_message": "Object is busy."}`))
+		} else {
+			_, _ = w.Write([]byte(`{"error_code": 503040, "error_message": "Port has a VIF attached."}`))
+		}
+	}))
+	defer ts.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.MinDelay = time.Millisecond
+	c, err := NewClient(ts.URL, "", "", WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsp, err := c.DeleteSegmentPort(context.Background(), "seg", "port")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts.Load() != 3 {
+		t.Fatalf("expected the busy object to be retried until the conflict changed, got %d attempts", attempts.Load())
+	}
+	apiErr := ParseAPIError(rsp)
+	if apiErr.ErrorCode != 503040 {
+		t.Fatalf("expected the last conflict to be returned, got %v", apiErr)
+	}
+}
+
+func TestRetryPolicyContextCancelled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, "", "", WithRetryPolicy(DefaultRetryPolicy()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = c.DeleteSegmentPort(ctx, "seg", "port")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("retry did not stop when the context was cancelled")
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// SearchParams defines the optional query parameters for Search.
+type SearchParams struct {
+	// Cursor is the opaque cursor returned with the previous page.
+	Cursor string
+	// PageSize is the maximum number of results per page. NSX defaults to 1000.
+	PageSize int
+	// SortBy is the field to sort results on.
+	SortBy        string
+	SortAscending *bool
+}
+
+// SearchResponse is a page of search results. Results are left encoded as
+// their type depends on the query.
+type SearchResponse struct {
+	Cursor      string            `json:"cursor"`
+	ResultCount int               `json:"result_count"`
+	Results     []json.RawMessage `json:"results"`
+}
+
+// Search runs query, a Lucene query string such as
+// "resource_type:SegmentPort AND display_name:web*", against every policy
+// object NSX has indexed.
+func (c *Client) Search(ctx context.Context, query string, params *SearchParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSearchRequest(c.Server, c.PolicyContext, query, params)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+// NewSearchRequest builds a search request. NSX searches the default space
+// unless the context parameter names a project.
+func NewSearchRequest(server string, pc PolicyContext, query string, params *SearchParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := pc.APIPath() + "/search/query"
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	queryValues := queryURL.Query()
+	queryValues.Set("query", query)
+	if pc.ProjectId != "" {
+		queryValues.Set("context", "projects:"+pc.ProjectId)
+	}
+	if params != nil {
+		if params.Cursor != "" {
+			queryValues.Set("cursor", params.Cursor)
+		}
+		if params.PageSize > 0 {
+			queryValues.Set("page_size", strconv.Itoa(params.PageSize))
+		}
+		if params.SortBy != "" {
+			queryValues.Set("sort_by", params.SortBy)
+		}
+		if params.SortAscending != nil {
+			queryValues.Set("sort_ascending", strconv.FormatBool(*params.SortAscending))
+		}
+	}
+	queryURL.RawQuery = queryValues.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// SearchResults iterates over every result of query, following the cursor
+// from page to page. Iteration stops after the first error.
+func (c *Client) SearchResults(ctx context.Context, query string, params *SearchParams, reqEditors ...RequestEditorFn) iter.Seq2[json.RawMessage, error] {
+	return paginate[json.RawMessage](func(cursor string) (*http.Response, error) {
+		var page SearchParams
+		if params != nil {
+			page = *params
+		}
+		if cursor != "" {
+			page.Cursor = cursor
+		}
+		return c.Search(ctx, query, &page, reqEditors...)
+	}, "search results")
+}
+
+// SearchSegmentPorts returns every segment port matching query, reading all
+// pages. The query is restricted to segment ports, so it only needs to
+// match their fields, such as "attachment.traffic_tag:1001".
+func (c *Client) SearchSegmentPorts(ctx context.Context, query string, params *SearchParams, reqEditors ...RequestEditorFn) ([]SegmentPort, error) {
+	if query != "" {
+		query = "resource_type:SegmentPort AND (" + query + ")"
+	} else {
+		query = "resource_type:SegmentPort"
+	}
+
+	var ports []SegmentPort
+	for result, err := range c.SearchResults(ctx, query, params, reqEditors...) {
+		if err != nil {
+			return nil, err
+		}
+		var port SegmentPort
+		if err := json.Unmarshal(result, &port); err != nil {
+			return nil, fmt.Errorf("invalid format received for segment port: %w", err)
+		}
+		ports = append(ports, port)
+	}
+	return ports, nil
+}
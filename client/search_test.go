@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestSearchSegmentPorts(t *testing.T) {
+	const total = 5
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/policy/api/v1/search/query" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if q := r.URL.Query().Get("query"); q != "resource_type:SegmentPort AND (attachment.traffic_tag:1001)" {
+			t.Errorf("unexpected query %q", q)
+		}
+		if c := r.URL.Query().Get("context"); c != "projects:dev" {
+			t.Errorf("unexpected context %q", c)
+		}
+		start, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+
+		body := map[string]any{"result_count": total}
+		var results []SegmentPort
+		for i := start; i < min(start+2, total); i++ {
+			results = append(results, SegmentPort{Id: fmt.Sprintf("port-%d", i), ResourceType: "SegmentPort"})
+		}
+		body["results"] = results
+		if start+2 < total {
+			body["cursor"] = strconv.Itoa(start + 2)
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, "", "", WithPolicyContext(PolicyContext{ProjectId: "dev"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ports, err := c.SearchSegmentPorts(context.Background(), "attachment.traffic_tag:1001", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ports) != total {
+		t.Fatalf("expected %d ports, got %d", total, len(ports))
+	}
+	for i, port := range ports {
+		if port.Id != fmt.Sprintf("port-%d", i) {
+			t.Fatalf("unexpected port %d: %s", i, port.Id)
+		}
+	}
+}
+
+func TestSearchError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error_code": 60506, "error_message": "Invalid search query."}`))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.SearchSegmentPorts(context.Background(), "display_name:(", nil)
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.ErrorCode != 60506 {
+		t.Fatalf("expected the NSX error, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+)
+
+// Segment is a layer 2 broadcast domain. A segment in a VLAN transport zone
+// is backed by VlanIds, and an overlay segment may be connected to a tier-1
+// gateway through ConnectivityPath, which routes between the Subnets.
+type Segment struct {
+	AdminState        string          `json:"admin_state,omitempty"`
+	ConnectivityPath  string          `json:"connectivity_path,omitempty"`
+	Description       string          `json:"description,omitempty"`
+	DisplayName       string          `json:"display_name,omitempty"`
+	Id                string          `json:"id"`
+	ResourceType      string          `json:"resource_type"`
+	Subnets           []SegmentSubnet `json:"subnets,omitempty"`
+	Tags              []Tag           `json:"tags,omitempty"`
+	TransportZonePath string          `json:"transport_zone_path,omitempty"`
+	VlanIds           []string        `json:"vlan_ids,omitempty"`
+
+	// Fields set by NSX.
+	Path       string `json:"path,omitempty"`
+	ParentPath string `json:"parent_path,omitempty"`
+	UniqueId   string `json:"unique_id,omitempty"`
+
+	Revision         *int64 `json:"_revision,omitempty"`
+	LastModifiedUser string `json:"_last_modified_user,omitempty"`
+	LastModifiedTime int64  `json:"_last_modified_time,omitempty"`
+}
+
+// SegmentSubnet is a subnet of a segment. The segment's gateway has
+// GatewayAddress, a CIDR such as 10.0.1.1/24, on the subnet.
+type SegmentSubnet struct {
+	DhcpRanges     []string `json:"dhcp_ranges,omitempty"`
+	GatewayAddress string   `json:"gateway_address,omitempty"`
+	// Network is the network address of the subnet, set by NSX.
+	Network string `json:"network,omitempty"`
+}
+
+// ListSegmentsParams defines the optional query parameters for
+// ListSegments.
+type ListSegmentsParams struct {
+	// Cursor is the opaque cursor returned with the previous page.
+	Cursor string
+	// PageSize is the maximum number of results per page. NSX defaults to 1000.
+	PageSize int
+}
+
+type ListSegmentsResponse struct {
+	Cursor      string    `json:"cursor"`
+	ResultCount int       `json:"result_count"`
+	Results     []Segment `json:"results"`
+}
+
+type PatchSegmentRequest struct {
+	SegmentId string  `json:"segment_id"`
+	Segment   Segment `json:"segment"`
+}
+
+type UpdateSegmentRequest struct {
+	SegmentId string  `json:"segment_id"`
+	Segment   Segment `json:"segment"`
+	// Current is the segment as read from NSX, if set, which Segment is
+	// merged onto with MergeObject.
+	Current map[string]any `json:"-"`
+}
+
+// ListSegments lists the segments of the client's policy context, or the
+// subnets of a VPC context.
+func (c *Client) ListSegments(ctx context.Context, params *ListSegmentsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListSegmentsRequest(c.Server, c.PolicyContext, params)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewListSegmentsRequest(server string, pc PolicyContext, params *ListSegmentsParams) (*http.Request, error) {
+	queryValues := url.Values{}
+	if params != nil {
+		setPageParams(queryValues, params.Cursor, params.PageSize)
+	}
+	return newListRequest(server, pc.APIPath()+pc.SegmentsPath(), queryValues)
+}
+
+// Segments iterates over every segment, following the cursor from page to
+// page. Iteration stops after the first error.
+func (c *Client) Segments(ctx context.Context, params *ListSegmentsParams, reqEditors ...RequestEditorFn) iter.Seq2[Segment, error] {
+	return paginate[Segment](func(cursor string) (*http.Response, error) {
+		var page ListSegmentsParams
+		if params != nil {
+			page = *params
+		}
+		if cursor != "" {
+			page.Cursor = cursor
+		}
+		return c.ListSegments(ctx, &page, reqEditors...)
+	}, "segments")
+}
+
+// ListAllSegments returns every segment, reading all pages.
+func (c *Client) ListAllSegments(ctx context.Context, params *ListSegmentsParams, reqEditors ...RequestEditorFn) ([]Segment, error) {
+	var segments []Segment
+	for segment, err := range c.Segments(ctx, params, reqEditors...) {
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, segment)
+	}
+	return segments, nil
+}
+
+func (c *Client) GetSegment(ctx context.Context, segment_id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetSegmentRequest(c.Server, c.PolicyContext, segment_id)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewGetSegmentRequest(server string, pc PolicyContext, segment_id string) (*http.Request, error) {
+	return newSegmentRequest(server, pc, http.MethodGet, segment_id, nil)
+}
+
+func (c *Client) PatchSegment(ctx context.Context, body PatchSegmentRequest, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPatchSegmentRequest(c.Server, c.PolicyContext, body)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewPatchSegmentRequest(server string, pc PolicyContext, body PatchSegmentRequest) (*http.Request, error) {
+	return newSegmentRequest(server, pc, http.MethodPatch, body.SegmentId, body.Segment)
+}
+
+// UpdateSegment replaces a segment. NSX rejects the request with 412
+// Precondition Failed if the segment's revision doesn't match
+// body.Segment.Revision.
+func (c *Client) UpdateSegment(ctx context.Context, body UpdateSegmentRequest, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateSegmentRequest(c.Server, c.PolicyContext, body)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewUpdateSegmentRequest(server string, pc PolicyContext, body UpdateSegmentRequest) (*http.Request, error) {
+	obj, err := MergeObject(body.Current, body.Segment)
+	if err != nil {
+		return nil, err
+	}
+	return newSegmentRequest(server, pc, http.MethodPut, body.SegmentId, obj)
+}
+
+// DeleteSegment deletes a segment. NSX refuses to delete a segment which
+// still has ports.
+func (c *Client) DeleteSegment(ctx context.Context, segment_id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteSegmentRequest(c.Server, c.PolicyContext, segment_id)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewDeleteSegmentRequest(server string, pc PolicyContext, segment_id string) (*http.Request, error) {
+	return newSegmentRequest(server, pc, http.MethodDelete, segment_id, nil)
+}
+
+// newSegmentRequest builds a request for the segment segment_id, with
+// segment as its body unless it is nil.
+func newSegmentRequest(server string, pc PolicyContext, method string, segment_id string, segment any) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := pc.APIPath() + pc.SegmentPath(segment_id)
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var bodyReader io.Reader
+	if segment != nil {
+		buf, err := json.Marshal(segment)
+		if err != nil {
+			return nil, err
+		}
+		bodyReader = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequest(method, queryURL.String(), bodyReader)
+	if err != nil {
+		return nil, err
+	}
+
+	if bodyReader != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
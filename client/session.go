@@ -0,0 +1,190 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	sessionCookieName = "JSESSIONID"
+	xsrfTokenHeader   = "X-XSRF-TOKEN"
+)
+
+// SessionAuthenticator authenticates requests using an NSX API session
+// rather than per-request basic auth. The session is created lazily on the
+// first request and re-created whenever NSX rejects it.
+type SessionAuthenticator struct {
+	server   string
+	username string
+	password string
+	doer     HttpRequestDoer
+
+	mu        sync.Mutex
+	session   *http.Cookie
+	xsrfToken string
+}
+
+func NewSessionAuthenticator(server string, username string, password string, doer HttpRequestDoer) *SessionAuthenticator {
+	return &SessionAuthenticator{
+		server:   server,
+		username: username,
+		password: password,
+		doer:     doer,
+	}
+}
+
+// Intercept is a RequestEditorFn which adds the session cookie and XSRF
+// token to the request, creating the session first if required.
+func (s *SessionAuthenticator) Intercept(ctx context.Context, req *http.Request) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session == nil {
+		if err := s.create(ctx); err != nil {
+			return err
+		}
+	}
+	req.AddCookie(s.session)
+	req.Header.Set(xsrfTokenHeader, s.xsrfToken)
+	return nil
+}
+
+// Create logs in to NSX and stores the resulting session, replacing any
+// existing one.
+func (s *SessionAuthenticator) Create(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.create(ctx)
+}
+
+// Renew re-creates the session used by a request that NSX rejected. If
+// another request has already renewed it in the meantime, the current
+// session is kept.
+func (s *SessionAuthenticator) Renew(ctx context.Context, rejected *http.Request) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session != nil && rejected.Header.Get(xsrfTokenHeader) != s.xsrfToken {
+		return nil
+	}
+	return s.create(ctx)
+}
+
+// Destroy logs out of NSX. It is a no-op if no session was ever created.
+func (s *SessionAuthenticator) Destroy(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session == nil {
+		return nil
+	}
+	req, err := NewDestroySessionRequest(s.server)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.AddCookie(s.session)
+	req.Header.Set(xsrfTokenHeader, s.xsrfToken)
+
+	s.session = nil
+	s.xsrfToken = ""
+
+	rsp, err := s.doer.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to destroy NSX session: %s", rsp.Status)
+	}
+	return nil
+}
+
+func (s *SessionAuthenticator) create(ctx context.Context) error {
+	req, err := NewCreateSessionRequest(s.server, s.username, s.password)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	rsp, err := s.doer.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to create NSX session: %s", rsp.Status)
+	}
+
+	var session *http.Cookie
+	for _, cookie := range rsp.Cookies() {
+		if cookie.Name == sessionCookieName {
+			session = cookie
+		}
+	}
+	if session == nil {
+		return fmt.Errorf("unable to create NSX session: no %s cookie in response", sessionCookieName)
+	}
+
+	s.session = &http.Cookie{Name: session.Name, Value: session.Value}
+	s.xsrfToken = rsp.Header.Get(xsrfTokenHeader)
+	return nil
+}
+
+func NewCreateSessionRequest(server string, user string, pass string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	queryURL, err := serverURL.Parse("/api/session/create")
+	if err != nil {
+		return nil, err
+	}
+
+	creds := url.Values{}
+	creds.Set("j_username", user)
+	creds.Set("j_password", pass)
+
+	req, err := http.NewRequest(http.MethodPost, queryURL.String(), strings.NewReader(creds.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	return req, nil
+}
+
+func NewDestroySessionRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	queryURL, err := serverURL.Parse("/api/session/destroy")
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type sessionServer struct {
+	mu        sync.Mutex
+	sessions  int
+	destroyed int
+	valid     string
+}
+
+func (s *sessionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/api/session/create":
+		if r.FormValue("j_username") != "admin" || r.FormValue("j_password") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		s.sessions++
+		s.valid = fmt.Sprintf("session-%d", s.sessions)
+		http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: s.valid, Path: "/", HttpOnly: true})
+		w.Header().Set("x-xsrf-token", "token-"+s.valid)
+	case "/api/session/destroy":
+		s.destroyed++
+		s.valid = ""
+	default:
+		cookie, err := r.Cookie("JSESSIONID")
+		if err != nil || cookie.Value != s.valid || r.Header.Get("X-XSRF-TOKEN") != "token-"+s.valid {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"result_count": 0, "results": []}`)
+	}
+}
+
+func (s *sessionServer) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.valid = "expired"
+}
+
+func TestSessionAuthentication(t *testing.T) {
+	srv := &sessionServer{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, "admin", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	rsp, err := c.ListSegmentPorts(ctx, "seg", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rsp.StatusCode)
+	}
+
+	// An expired session is transparently re-created and the request replayed.
+	srv.expire()
+	rsp, err = c.PatchSegmentPort(ctx, PatchSegmentPortRequest{SegmentId: "seg", PortId: "port"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 after session renewal, got %d", rsp.StatusCode)
+	}
+	if srv.sessions != 2 {
+		t.Fatalf("expected 2 sessions, got %d", srv.sessions)
+	}
+
+	if err := c.CloseSession(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if srv.destroyed != 1 {
+		t.Fatalf("expected session to be destroyed")
+	}
+}
+
+func TestSessionAuthenticationBadCredentials(t *testing.T) {
+	ts := httptest.NewServer(&sessionServer{})
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, "admin", "wrong")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Session.Create(context.Background()); err == nil {
+		t.Fatal("expected an error creating a session with bad credentials")
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// StaticRoute routes Network through the NextHops of a tier-1 gateway, such
+// as the child port addresses of a VM routing for the networks behind it.
+type StaticRoute struct {
+	Description  string          `json:"description,omitempty"`
+	DisplayName  string          `json:"display_name,omitempty"`
+	Enabled      *bool           `json:"enabled,omitempty"`
+	Id           string          `json:"id"`
+	Network      string          `json:"network"`
+	NextHops     []RouterNexthop `json:"next_hops"`
+	ResourceType string          `json:"resource_type"`
+	Tags         []Tag           `json:"tags,omitempty"`
+
+	// Fields set by NSX.
+	Path       string `json:"path,omitempty"`
+	ParentPath string `json:"parent_path,omitempty"`
+	UniqueId   string `json:"unique_id,omitempty"`
+
+	Revision         *int64 `json:"_revision,omitempty"`
+	LastModifiedUser string `json:"_last_modified_user,omitempty"`
+	LastModifiedTime int64  `json:"_last_modified_time,omitempty"`
+}
+
+// RouterNexthop is a next hop of a static route. Scope limits the route to
+// the gateway interfaces or segments with these policy paths.
+type RouterNexthop struct {
+	AdminDistance int64    `json:"admin_distance,omitempty"`
+	IpAddress     string   `json:"ip_address"`
+	Scope         []string `json:"scope,omitempty"`
+}
+
+type PatchStaticRouteRequest struct {
+	Tier1Id     string      `json:"tier1_id"`
+	StaticRoute StaticRoute `json:"static_route"`
+}
+
+type UpdateStaticRouteRequest struct {
+	Tier1Id     string      `json:"tier1_id"`
+	StaticRoute StaticRoute `json:"static_route"`
+	// Current is the route as read from NSX, if set, which StaticRoute is
+	// merged onto with MergeObject.
+	Current map[string]any `json:"-"`
+}
+
+func (c *Client) GetStaticRoute(ctx context.Context, tier1_id string, route_id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetStaticRouteRequest(c.Server, c.PolicyContext, tier1_id, route_id)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewGetStaticRouteRequest(server string, pc PolicyContext, tier1_id string, route_id string) (*http.Request, error) {
+	return newPolicyObjectRequest(server, pc, http.MethodGet, pc.StaticRoutePath(tier1_id, route_id), nil)
+}
+
+func (c *Client) PatchStaticRoute(ctx context.Context, body PatchStaticRouteRequest, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPatchStaticRouteRequest(c.Server, c.PolicyContext, body)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewPatchStaticRouteRequest(server string, pc PolicyContext, body PatchStaticRouteRequest) (*http.Request, error) {
+	return newPolicyObjectRequest(server, pc, http.MethodPatch, pc.StaticRoutePath(body.Tier1Id, body.StaticRoute.Id), body.StaticRoute)
+}
+
+// UpdateStaticRoute replaces a static route. NSX rejects the request with
+// 412 Precondition Failed if the route's revision doesn't match
+// body.StaticRoute.Revision.
+func (c *Client) UpdateStaticRoute(ctx context.Context, body UpdateStaticRouteRequest, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateStaticRouteRequest(c.Server, c.PolicyContext, body)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewUpdateStaticRouteRequest(server string, pc PolicyContext, body UpdateStaticRouteRequest) (*http.Request, error) {
+	obj, err := MergeObject(body.Current, body.StaticRoute)
+	if err != nil {
+		return nil, err
+	}
+	return newPolicyObjectRequest(server, pc, http.MethodPut, pc.StaticRoutePath(body.Tier1Id, body.StaticRoute.Id), obj)
+}
+
+func (c *Client) DeleteStaticRoute(ctx context.Context, tier1_id string, route_id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteStaticRouteRequest(c.Server, c.PolicyContext, tier1_id, route_id)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewDeleteStaticRouteRequest(server string, pc PolicyContext, tier1_id string, route_id string) (*http.Request, error) {
+	return newPolicyObjectRequest(server, pc, http.MethodDelete, pc.StaticRoutePath(tier1_id, route_id), nil)
+}
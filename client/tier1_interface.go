@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Tier1Interface is a service interface of a tier-1 gateway, which connects
+// the gateway to a segment that isn't connected to it through the segment's
+// connectivity path, such as a VLAN backed segment. The gateway routes
+// between the Subnets of its interfaces.
+type Tier1Interface struct {
+	Description  string            `json:"description,omitempty"`
+	DisplayName  string            `json:"display_name,omitempty"`
+	Id           string            `json:"id"`
+	Mtu          *int64            `json:"mtu,omitempty"`
+	ResourceType string            `json:"resource_type"`
+	SegmentPath  string            `json:"segment_path"`
+	Subnets      []InterfaceSubnet `json:"subnets"`
+	Tags         []Tag             `json:"tags,omitempty"`
+	// UrpfMode is the unicast reverse path forwarding mode, STRICT or NONE.
+	UrpfMode string `json:"urpf_mode,omitempty"`
+
+	// Fields set by NSX.
+	Path       string `json:"path,omitempty"`
+	ParentPath string `json:"parent_path,omitempty"`
+	UniqueId   string `json:"unique_id,omitempty"`
+
+	Revision         *int64 `json:"_revision,omitempty"`
+	LastModifiedUser string `json:"_last_modified_user,omitempty"`
+	LastModifiedTime int64  `json:"_last_modified_time,omitempty"`
+}
+
+// InterfaceSubnet is a subnet of a gateway interface, such as IpAddresses
+// ["10.0.1.1"] with PrefixLen 24.
+type InterfaceSubnet struct {
+	IpAddresses []string `json:"ip_addresses"`
+	PrefixLen   int64    `json:"prefix_len"`
+}
+
+type PatchTier1InterfaceRequest struct {
+	Tier1Id         string         `json:"tier1_id"`
+	LocaleServiceId string         `json:"locale_service_id"`
+	Interface       Tier1Interface `json:"interface"`
+}
+
+type UpdateTier1InterfaceRequest struct {
+	Tier1Id         string         `json:"tier1_id"`
+	LocaleServiceId string         `json:"locale_service_id"`
+	Interface       Tier1Interface `json:"interface"`
+	// Current is the interface as read from NSX, if set, which Interface
+	// is merged onto with MergeObject.
+	Current map[string]any `json:"-"`
+}
+
+func (c *Client) GetTier1Interface(ctx context.Context, tier1_id string, locale_service_id string, interface_id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetTier1InterfaceRequest(c.Server, c.PolicyContext, tier1_id, locale_service_id, interface_id)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewGetTier1InterfaceRequest(server string, pc PolicyContext, tier1_id string, locale_service_id string, interface_id string) (*http.Request, error) {
+	return newPolicyObjectRequest(server, pc, http.MethodGet, pc.Tier1InterfacePath(tier1_id, locale_service_id, interface_id), nil)
+}
+
+func (c *Client) PatchTier1Interface(ctx context.Context, body PatchTier1InterfaceRequest, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPatchTier1InterfaceRequest(c.Server, c.PolicyContext, body)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewPatchTier1InterfaceRequest(server string, pc PolicyContext, body PatchTier1InterfaceRequest) (*http.Request, error) {
+	return newPolicyObjectRequest(server, pc, http.MethodPatch, pc.Tier1InterfacePath(body.Tier1Id, body.LocaleServiceId, body.Interface.Id), body.Interface)
+}
+
+// UpdateTier1Interface replaces a service interface. NSX rejects the request
+// with 412 Precondition Failed if the interface's revision doesn't match
+// body.Interface.Revision.
+func (c *Client) UpdateTier1Interface(ctx context.Context, body UpdateTier1InterfaceRequest, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateTier1InterfaceRequest(c.Server, c.PolicyContext, body)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewUpdateTier1InterfaceRequest(server string, pc PolicyContext, body UpdateTier1InterfaceRequest) (*http.Request, error) {
+	obj, err := MergeObject(body.Current, body.Interface)
+	if err != nil {
+		return nil, err
+	}
+	return newPolicyObjectRequest(server, pc, http.MethodPut, pc.Tier1InterfacePath(body.Tier1Id, body.LocaleServiceId, body.Interface.Id), obj)
+}
+
+func (c *Client) DeleteTier1Interface(ctx context.Context, tier1_id string, locale_service_id string, interface_id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteTier1InterfaceRequest(c.Server, c.PolicyContext, tier1_id, locale_service_id, interface_id)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewDeleteTier1InterfaceRequest(server string, pc PolicyContext, tier1_id string, locale_service_id string, interface_id string) (*http.Request, error) {
+	return newPolicyObjectRequest(server, pc, http.MethodDelete, pc.Tier1InterfacePath(tier1_id, locale_service_id, interface_id), nil)
+}
+
+// newPolicyObjectRequest builds a request for the policy object at
+// policyPath, with obj as its body unless it is nil.
+func newPolicyObjectRequest(server string, pc PolicyContext, method string, policyPath string, obj any) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := pc.APIPath() + policyPath
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var bodyReader io.Reader
+	if obj != nil {
+		buf, err := json.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		bodyReader = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequest(method, queryURL.String(), bodyReader)
+	if err != nil {
+		return nil, err
+	}
+
+	if bodyReader != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ParseCertificateFingerprint parses a SHA-256 certificate fingerprint,
+// written in hex with or without colons, such as the one shown by
+// `openssl x509 -noout -fingerprint -sha256`.
+func ParseCertificateFingerprint(fingerprint string) ([]byte, error) {
+	s := strings.TrimSpace(fingerprint)
+	s = strings.TrimPrefix(strings.ToLower(s), "sha256:")
+	s = strings.ReplaceAll(s, ":", "")
+	sum, err := hex.DecodeString(s)
+	if err != nil || len(sum) != sha256.Size {
+		return nil, fmt.Errorf("invalid SHA-256 certificate fingerprint %q: expected %d hex encoded bytes", fingerprint, sha256.Size)
+	}
+	return sum, nil
+}
+
+// verifyFingerprint returns a tls.Config VerifyConnection callback which
+// accepts the connection only if the server's leaf certificate has the SHA-256
+// fingerprint sum.
+func verifyFingerprint(sum []byte) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("NSX Manager presented no certificate")
+		}
+		actual := sha256.Sum256(cs.PeerCertificates[0].Raw)
+		if !bytes.Equal(actual[:], sum) {
+			return fmt.Errorf("NSX Manager certificate fingerprint %s does not match the pinned fingerprint %s",
+				formatFingerprint(actual[:]), formatFingerprint(sum))
+		}
+		return nil
+	}
+}
+
+func formatFingerprint(sum []byte) string {
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// certPool returns a pool of the PEM encoded CA certificates.
+func certPool(pem []byte) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no PEM encoded CA certificates found")
+	}
+	return pool, nil
+}
+
+// proxyFunc returns an http.Transport Proxy function which sends every
+// request through proxyURL, except requests to hosts excluded by the
+// NO_PROXY environment variable.
+func proxyFunc(proxyURL *url.URL) func(*http.Request) (*url.URL, error) {
+	cfg := httpproxy.FromEnvironment()
+	cfg.HTTPProxy = proxyURL.String()
+	cfg.HTTPSProxy = proxyURL.String()
+	proxy := cfg.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func newTLSTestServer(t *testing.T) *httptest.Server {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"results": []}`)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func listSegmentPorts(c *Client) error {
+	rsp, err := c.ListSegmentPorts(context.Background(), "seg", nil)
+	if err != nil {
+		return err
+	}
+	rsp.Body.Close()
+	return nil
+}
+
+func TestWithCACertificates(t *testing.T) {
+	ts := newTLSTestServer(t)
+
+	c, err := NewClient(ts.URL, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := listSegmentPorts(c); err == nil {
+		t.Fatal("expected the test server certificate to be rejected without its CA")
+	}
+
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	c, err = NewClient(ts.URL, "", "", WithCACertificates(ca))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := listSegmentPorts(c); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewClient(ts.URL, "", "", WithCACertificates([]byte("not a certificate"))); err == nil {
+		t.Fatal("expected an error for an invalid CA bundle")
+	}
+	if _, err := NewClient(ts.URL, "", "", WithCACertificates(ca), WithInsecure(true)); err == nil {
+		t.Fatal("expected insecure connections combined with CA certificates to be rejected")
+	}
+}
+
+func TestWithCertificateFingerprint(t *testing.T) {
+	ts := newTLSTestServer(t)
+	sum := sha256.Sum256(ts.Certificate().Raw)
+
+	c, err := NewClient(ts.URL, "", "", WithCertificateFingerprint(formatFingerprint(sum[:])))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := listSegmentPorts(c); err != nil {
+		t.Fatal(err)
+	}
+
+	sum[0]++
+	c, err = NewClient(ts.URL, "", "", WithCertificateFingerprint(strings.ToLower(formatFingerprint(sum[:]))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = listSegmentPorts(c)
+	if err == nil || !strings.Contains(err.Error(), "does not match the pinned fingerprint") {
+		t.Fatalf("expected a fingerprint mismatch, got %v", err)
+	}
+
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	if _, err := NewClient(ts.URL, "", "", WithCACertificates(ca), WithCertificateFingerprint(formatFingerprint(sum[:]))); err == nil {
+		t.Fatal("expected a fingerprint combined with CA certificates to be rejected")
+	}
+}
+
+func TestParseCertificateFingerprint(t *testing.T) {
+	for _, fingerprint := range []string{
+		strings.Repeat("AB:", 31) + "AB",
+		strings.Repeat("ab", 32),
+		"sha256:" + strings.Repeat("ab", 32),
+	} {
+		if _, err := ParseCertificateFingerprint(fingerprint); err != nil {
+			t.Errorf("%s: %v", fingerprint, err)
+		}
+	}
+	for _, fingerprint := range []string{"", "AB:CD", strings.Repeat("zz", 32)} {
+		if _, err := ParseCertificateFingerprint(fingerprint); err == nil {
+			t.Errorf("expected %q to be rejected", fingerprint)
+		}
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		_, _ = io.WriteString(w, `{"results": []}`)
+	}))
+	defer proxy.Close()
+
+	t.Setenv("NO_PROXY", "")
+	c, err := NewClient("http://nsx.example.com", "", "", WithProxy(proxy.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := listSegmentPorts(c); err != nil {
+		t.Fatal(err)
+	}
+	if len(proxied) != 1 || !strings.HasPrefix(proxied[0], "http://nsx.example.com/policy/api/v1/infra/segments/seg/ports") {
+		t.Fatalf("expected the request to go through the proxy, got %q", proxied)
+	}
+
+	if _, err := NewClient("http://nsx.example.com", "", "", WithProxy("proxy.example.com")); err == nil {
+		t.Fatal("expected an error for a proxy URL without a scheme")
+	}
+}
+
+func TestProxyNoProxy(t *testing.T) {
+	proxyURL, _ := url.Parse("http://proxy.example.com:3128")
+	t.Setenv("NO_PROXY", "localhost, .corp.example.com,10.0.0.0/8,nsx.example.org:443")
+	proxy := proxyFunc(proxyURL)
+	for host, bypass := range map[string]bool{
+		"localhost":             true,
+		"nsx.corp.example.com":  true,
+		"10.1.2.3":              true,
+		"nsx.example.org":       true,
+		"nsx.example.com":       false,
+		"192.168.0.1":           false,
+		"corp.example.com":      false,
+		"evilcorp.example.com":  false,
+		"nsx.example.org.other": false,
+	} {
+		req, err := http.NewRequest(http.MethodGet, "https://"+host+"/policy/api/v1/infra", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := proxy(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (u == nil) != bypass {
+			t.Errorf("expected %s to bypass the proxy: %t, got proxy %v", host, bypass, u)
+		}
+	}
+
+	t.Setenv("NO_PROXY", "*")
+	req, _ := http.NewRequest(http.MethodGet, "https://nsx.example.com/policy/api/v1/infra", nil)
+	if u, _ := proxyFunc(proxyURL)(req); u != nil {
+		t.Errorf("expected * to bypass the proxy for every host, got %v", u)
+	}
+}
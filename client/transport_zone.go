@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+)
+
+// TransportZone is the span of the segments in it, the transport nodes
+// which can reach them. Segments in a VLAN_BACKED transport zone are
+// backed by VLAN IDs, and those in an OVERLAY_BACKED one by overlay
+// networks.
+type TransportZone struct {
+	Description  string `json:"description,omitempty"`
+	DisplayName  string `json:"display_name,omitempty"`
+	Id           string `json:"id"`
+	IsDefault    bool   `json:"is_default,omitempty"`
+	ResourceType string `json:"resource_type"`
+	Tags         []Tag  `json:"tags,omitempty"`
+	TzType       string `json:"tz_type,omitempty"`
+
+	// Fields set by NSX.
+	Path       string `json:"path,omitempty"`
+	ParentPath string `json:"parent_path,omitempty"`
+	UniqueId   string `json:"unique_id,omitempty"`
+}
+
+// ListTransportZonesParams defines the optional query parameters for
+// ListTransportZones.
+type ListTransportZonesParams struct {
+	// Cursor is the opaque cursor returned with the previous page.
+	Cursor string
+	// PageSize is the maximum number of results per page. NSX defaults to 1000.
+	PageSize int
+}
+
+type ListTransportZonesResponse struct {
+	Cursor      string          `json:"cursor"`
+	ResultCount int             `json:"result_count"`
+	Results     []TransportZone `json:"results"`
+}
+
+// ListTransportZones lists the transport zones of an enforcement point of a
+// site. An empty site or enforcement point selects the default, that of a
+// standalone NSX Manager.
+func (c *Client) ListTransportZones(ctx context.Context, site string, enforcement_point string, params *ListTransportZonesParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListTransportZonesRequest(c.Server, c.PolicyContext, site, enforcement_point, params)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, reqEditors)
+}
+
+func NewListTransportZonesRequest(server string, pc PolicyContext, site string, enforcement_point string, params *ListTransportZonesParams) (*http.Request, error) {
+	queryValues := url.Values{}
+	if params != nil {
+		setPageParams(queryValues, params.Cursor, params.PageSize)
+	}
+	return newListRequest(server, pc.APIPath()+pc.TransportZonesPath(site, enforcement_point), queryValues)
+}
+
+// TransportZones iterates over every transport zone of an enforcement
+// point, following the cursor from page to page. Iteration stops after the
+// first error.
+func (c *Client) TransportZones(ctx context.Context, site string, enforcement_point string, params *ListTransportZonesParams, reqEditors ...RequestEditorFn) iter.Seq2[TransportZone, error] {
+	return paginate[TransportZone](func(cursor string) (*http.Response, error) {
+		var page ListTransportZonesParams
+		if params != nil {
+			page = *params
+		}
+		if cursor != "" {
+			page.Cursor = cursor
+		}
+		return c.ListTransportZones(ctx, site, enforcement_point, &page, reqEditors...)
+	}, "transport zones")
+}
+
+// ListAllTransportZones returns every transport zone of an enforcement
+// point, reading all pages.
+func (c *Client) ListAllTransportZones(ctx context.Context, site string, enforcement_point string, params *ListTransportZonesParams, reqEditors ...RequestEditorFn) ([]TransportZone, error) {
+	var tzs []TransportZone
+	for tz, err := range c.TransportZones(ctx, site, enforcement_point, params, reqEditors...) {
+		if err != nil {
+			return nil, err
+		}
+		tzs = append(tzs, tz)
+	}
+	return tzs, nil
+}
@@ -0,0 +1,5 @@
+# The CHILD port of a segment tagging traffic with VLAN 1001
+data "nsxt_intervlan_routing_segment_port" "vlan_1001" {
+  segment_id  = "4d4c0f0a-6c50-420b-90f1-68fb7585cda4"
+  traffic_tag = "1001"
+}
@@ -0,0 +1,4 @@
+# Every CHILD port tagging traffic with VLAN 1001, on any segment
+data "nsxt_intervlan_routing_segment_port_search" "vlan_1001" {
+  query = "attachment.type:CHILD AND attachment.traffic_tag:1001"
+}
@@ -1,4 +1,3 @@
 data "nsxt_intervlan_routing_segment_ports" "example" {
-  segment_id    = "4d4c0f0a-6c5 0-420b-90f1-68fb7585cda4"
-  segment_ports = "a274ac51-88f5-491f-a46f-840d409ce82f"
-}
\ No newline at end of file
+  segment_id = "4d4c0f0a-6c50-420b-90f1-68fb7585cda4"
+}
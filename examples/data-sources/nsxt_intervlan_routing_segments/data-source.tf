@@ -0,0 +1,26 @@
+# The VLAN segments of the firewall, one per VLAN
+data "nsxt_intervlan_routing_segments" "firewall" {
+  display_name_regex  = "^GCVE-PA-VLAN-"
+  transport_zone_path = "/infra/sites/default/enforcement-points/default/transport-zones/1b3a2f36-bfd1-443e-a0f6-4de01abc963e"
+}
+
+# The segment backed by VLAN 1001
+data "nsxt_intervlan_routing_segments" "vlan_1001" {
+  vlan_ids = ["1001"]
+}
+
+resource "nsxt_intervlan_routing_segment_port" "child_1001" {
+  segment_id = data.nsxt_intervlan_routing_segments.vlan_1001.segments[0].id
+  port_id    = "a274ac51-88f5-491f-a46f-840d409ce82f"
+  segment_port = {
+    attachment = {
+      context_id  = "9765bf41-9725-4714-977e-7f7395920de2"
+      traffic_tag = "1001"
+      app_id      = "Segment1001"
+      type        = "CHILD"
+    }
+    display_name  = "GCVE-PA-VM-ESX-2 Child Port 1001"
+    id            = "a274ac51-88f5-491f-a46f-840d409ce82f"
+    resource_type = "SegmentPort"
+  }
+}
@@ -0,0 +1,4 @@
+data "nsxt_intervlan_routing_transport_zones" "vlan" {
+  display_name = "nsx-vlan-transportzone"
+  tz_type      = "VLAN_BACKED"
+}
@@ -0,0 +1,8 @@
+# Find the VM and MAC address behind a PARENT port's attachment
+data "nsxt_intervlan_routing_vifs" "parent" {
+  lport_attachment_id = "9765bf41-9725-4714-977e-7f7395920de2"
+}
+
+output "parent_vm" {
+  value = data.nsxt_intervlan_routing_vifs.parent.vifs[0].owner_vm_id
+}
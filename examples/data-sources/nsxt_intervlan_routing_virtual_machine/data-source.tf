@@ -0,0 +1,19 @@
+data "nsxt_intervlan_routing_virtual_machine" "firewall" {
+  display_name = "GCVE-PA-VM-ESX-2"
+}
+
+# Attach the PARENT port to the VM's first VIF, and its CHILD ports to the
+# PARENT with the same attachment ID as their context_id
+resource "nsxt_intervlan_routing_segment_port" "parent_example" {
+  segment_id = "4d4c0f0a-6c50-420b-90f1-68fb7585cda4"
+  port_id    = "060af2c2-e9ff-4686-866c-c0daab1748d6"
+  segment_port = {
+    attachment = {
+      id   = data.nsxt_intervlan_routing_virtual_machine.firewall.vifs[0].lport_attachment_id
+      type = "PARENT"
+    }
+    display_name  = "GCVE-PA-VM-ESX-2 Parent Port"
+    id            = "060af2c2-e9ff-4686-866c-c0daab1748d6"
+    resource_type = "SegmentPort"
+  }
+}
@@ -14,8 +14,7 @@ provider "nsxt-intervlan-routing" {
 }
 
 data "nsxt_intervlan_routing_segment_ports" "example" {
-  segment_id    = ""
-  segment_ports = {}
+  segment_id = "4d4c0f0a-6c50-420b-90f1-68fb7585cda4"
 }
 
 resource "nsxt_intervlan_routing_segment_port" "parent_example" {
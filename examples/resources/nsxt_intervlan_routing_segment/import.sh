@@ -0,0 +1,5 @@
+# Segments are imported by their id in the provider context
+terraform import nsxt_intervlan_routing_segment.web "web"
+
+# or by their policy path in any other project
+terraform import nsxt_intervlan_routing_segment.project_example "/orgs/default/projects/dev/infra/segments/web"
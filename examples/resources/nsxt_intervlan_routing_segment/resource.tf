@@ -0,0 +1,17 @@
+resource "nsxt_intervlan_routing_segment" "web" {
+  id                = "web"
+  display_name      = "Web tier"
+  connectivity_path = "/infra/tier-1s/t1-gateway"
+  subnets = [
+    {
+      gateway_address = "10.0.1.1/24"
+      dhcp_ranges     = ["10.0.1.100-10.0.1.200"]
+    },
+  ]
+}
+
+resource "nsxt_intervlan_routing_segment" "vlan_example" {
+  id                  = "vlan-1001"
+  transport_zone_path = "/infra/sites/default/enforcement-points/default/transport-zones/vlan-tz"
+  vlan_ids            = ["1001"]
+}
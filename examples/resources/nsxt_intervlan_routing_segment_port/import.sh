@@ -1,2 +1,9 @@
-terraform import nsxt_intervlan_routing_segment_port.parent_example "4d4c0f0a-6c5 0-420b-90f1-68fb7585cda4" "a274ac51-88f5-491f-a46f-840d409ce82f"
-terraform import nsxt_intervlan_routing_segment_port.child_example "2bfe8abf-4161-4788-9cbe-c444e9bf7454" "a274ac51-88f5-491f-a46f-840d409ce82f"
\ No newline at end of file
+# Ports are imported by <segment_id>/<port_id> in the provider context
+terraform import nsxt_intervlan_routing_segment_port.parent_example "4d4c0f0a-6c50-420b-90f1-68fb7585cda4/a274ac51-88f5-491f-a46f-840d409ce82f"
+terraform import nsxt_intervlan_routing_segment_port.child_example "2bfe8abf-4161-4788-9cbe-c444e9bf7454/a274ac51-88f5-491f-a46f-840d409ce82f"
+
+# or by their policy path in any other project or VPC
+terraform import nsxt_intervlan_routing_segment_port.project_example "/orgs/default/projects/dev/infra/segments/4d4c0f0a-6c50-420b-90f1-68fb7585cda4/ports/a274ac51-88f5-491f-a46f-840d409ce82f"
+
+# or, with the provider's global_manager set, by their global policy path
+terraform import nsxt_intervlan_routing_segment_port.global_example "/global-infra/segments/4d4c0f0a-6c50-420b-90f1-68fb7585cda4/ports/a274ac51-88f5-491f-a46f-840d409ce82f"
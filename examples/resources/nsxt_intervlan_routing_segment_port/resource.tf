@@ -1,5 +1,5 @@
 resource "nsxt_intervlan_routing_segment_port" "parent_example" {
-  segment_id = "4d4c0f0a-6c5 0-420b-90f1-68fb7585cda4"
+  segment_id = "4d4c0f0a-6c50-420b-90f1-68fb7585cda4"
   port_id    = "a274ac51-88f5-491f-a46f-840d409ce82f"
   segment_port = {
     admin_state = "UP"
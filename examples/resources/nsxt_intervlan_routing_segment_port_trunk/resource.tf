@@ -0,0 +1,38 @@
+resource "nsxt_intervlan_routing_segment_port_trunk" "example" {
+  parent = {
+    segment_id = "4d4c0f0a-6c50-420b-90f1-68fb7585cda4"
+    port_id    = "060af2c2-e9ff-4686-866c-c0daab1748d6"
+    segment_port = {
+      admin_state = "UP"
+      attachment = {
+        id   = "9765bf41-9725-4714-977e-7f7395920de2"
+        type = "PARENT"
+      }
+      description   = "GCVE-PA-VM-ESX-2 Parent Port"
+      display_name  = "GCVE-PA-VM-ESX-2.vmx@060af2c2-e9ff-4686-866c-c0daab1748d6"
+      id            = "060af2c2-e9ff-4686-866c-c0daab1748d6"
+      resource_type = "SegmentPort"
+    }
+  }
+
+  children = [
+    for vlan in [1001, 1002, 1003] : {
+      segment_id = "2bfe8abf-4161-4788-9cbe-c444e9bf7454"
+      port_id    = "GCVE-PA-VM-ESX-2-${vlan}"
+      segment_port = {
+        admin_state = "UP"
+        attachment = {
+          id          = "GCVE-PA-VM-ESX-2-${vlan}"
+          context_id  = "9765bf41-9725-4714-977e-7f7395920de2"
+          traffic_tag = tostring(vlan)
+          app_id      = "Segment${vlan}"
+          type        = "CHILD"
+        }
+        description   = "GCVE-PA-VM-ESX-2 Child Port ${vlan}"
+        display_name  = "GCVE-PA-VM-ESX-2-${vlan}"
+        id            = "GCVE-PA-VM-ESX-2-${vlan}"
+        resource_type = "SegmentPort"
+      }
+    }
+  ]
+}
@@ -0,0 +1,5 @@
+# Static routes are imported by <tier1_id>/<id> in the provider context
+terraform import nsxt_intervlan_routing_static_route.behind_firewall "t1-gateway/behind-firewall"
+
+# or by their policy path in any other project
+terraform import nsxt_intervlan_routing_static_route.project_example "/orgs/default/projects/dev/infra/tier-1s/t1-gateway/static-routes/behind-firewall"
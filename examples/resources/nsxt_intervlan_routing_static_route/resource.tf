@@ -0,0 +1,17 @@
+# Route the networks behind a firewall VM through the addresses of its child
+# ports on two VLAN segments
+resource "nsxt_intervlan_routing_static_route" "behind_firewall" {
+  id       = "behind-firewall"
+  tier1_id = "t1-gateway"
+  network  = "192.168.0.0/16"
+  next_hops = [
+    {
+      ip_address = "10.0.101.254"
+      scope      = [nsxt_intervlan_routing_tier1_interface.vlan_1001.path]
+    },
+    {
+      ip_address     = "10.0.102.254"
+      admin_distance = 2
+    },
+  ]
+}
@@ -0,0 +1,5 @@
+# Interfaces are imported by <tier1_id>/<locale_service_id>/<id> in the provider context
+terraform import nsxt_intervlan_routing_tier1_interface.vlan_1001 "t1-gateway/default/vlan-1001"
+
+# or by their policy path in any other project
+terraform import nsxt_intervlan_routing_tier1_interface.project_example "/orgs/default/projects/dev/infra/tier-1s/t1-gateway/locale-services/default/interfaces/vlan-1001"
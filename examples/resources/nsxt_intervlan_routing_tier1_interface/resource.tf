@@ -0,0 +1,18 @@
+resource "nsxt_intervlan_routing_segment" "vlan_1001" {
+  id                  = "vlan-1001"
+  transport_zone_path = "/infra/sites/default/enforcement-points/default/transport-zones/vlan-tz"
+  vlan_ids            = ["1001"]
+}
+
+resource "nsxt_intervlan_routing_tier1_interface" "vlan_1001" {
+  id           = "vlan-1001"
+  tier1_id     = "t1-gateway"
+  segment_path = nsxt_intervlan_routing_segment.vlan_1001.path
+  subnets = [
+    {
+      ip_addresses = ["10.0.101.1"]
+      prefix_len   = 24
+    },
+  ]
+  mtu = 1500
+}
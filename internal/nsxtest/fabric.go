@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nsxtest
+
+import (
+	"net/http"
+
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+// AddVirtualMachine adds a VM and its VIFs to the fabric inventory, as if
+// discovered from a compute manager. The VIFs' owner_vm_id is set to the
+// VM's external id.
+func (s *Server) AddVirtualMachine(vm client.VirtualMachine, vifs ...client.VirtualNetworkInterface) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vm.ResourceType = "VirtualMachine"
+	var obj object
+	if err := convert(vm, &obj); err != nil {
+		panic(err)
+	}
+	s.vms = append(s.vms, obj)
+
+	for _, vif := range vifs {
+		vif.OwnerVmId = vm.ExternalId
+		vif.ResourceType = "VirtualNetworkInterface"
+		var obj object
+		if err := convert(vif, &obj); err != nil {
+			panic(err)
+		}
+		s.vifs = append(s.vifs, obj)
+	}
+}
+
+func (s *Server) listVirtualMachines(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.writePage(w, r, filterInventory(s.vms, r, "display_name", "external_id"))
+}
+
+func (s *Server) listVifs(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.writePage(w, r, filterInventory(s.vifs, r, "owner_vm_id", "lport_attachment_id"))
+}
+
+// filterInventory returns the objects whose fields equal the query
+// parameters of the same name which are set.
+func filterInventory(objects []object, r *http.Request, fields ...string) []object {
+	query := r.URL.Query()
+	var results []object
+	for _, obj := range objects {
+		matched := true
+		for _, field := range fields {
+			if value := query.Get(field); value != "" && obj[field] != value {
+				matched = false
+			}
+		}
+		if matched {
+			results = append(results, obj)
+		}
+	}
+	return results
+}
@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nsxtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+func TestFabricInventory(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.PageSize = 1
+	srv.AddVirtualMachine(client.VirtualMachine{DisplayName: "fw-1", ExternalId: "vm-1"},
+		client.VirtualNetworkInterface{ExternalId: "vm-1-4000", DeviceKey: "4000", LportAttachmentId: "att-1"},
+		client.VirtualNetworkInterface{ExternalId: "vm-1-4001", DeviceKey: "4001", LportAttachmentId: "att-2"},
+	)
+	srv.AddVirtualMachine(client.VirtualMachine{DisplayName: "fw-2", ExternalId: "vm-2"},
+		client.VirtualNetworkInterface{ExternalId: "vm-2-4000", DeviceKey: "4000", LportAttachmentId: "att-3"},
+	)
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	vms, err := c.ListAllVirtualMachines(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vms) != 2 {
+		t.Fatalf("expected every VM across pages, got %+v", vms)
+	}
+
+	vms, err = c.ListAllVirtualMachines(ctx, &client.ListVirtualMachinesParams{DisplayName: "fw-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vms) != 1 || vms[0].ExternalId != "vm-2" {
+		t.Fatalf("expected fw-2, got %+v", vms)
+	}
+
+	vifs, err := c.ListAllVifs(ctx, &client.ListVifsParams{OwnerVmId: "vm-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vifs) != 2 || vifs[1].LportAttachmentId != "att-2" || vifs[1].OwnerVmId != "vm-1" {
+		t.Fatalf("expected the VIFs of vm-1, got %+v", vifs)
+	}
+}
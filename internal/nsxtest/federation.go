@@ -0,0 +1,41 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nsxtest
+
+import (
+	"net/http"
+
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+// AddSite adds a Federation site with the default enforcement point, whose
+// Local Manager Global Manager reads can be served by.
+func (s *Server) AddSite(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.store(client.EnforcementPointPath(id, ""), object{
+		"id":            client.DefaultEnforcementPointId,
+		"display_name":  id,
+		"resource_type": "EnforcementPoint",
+	}, "admin")
+}
+
+// atEnforcementPoint rejects reads with an enforcement_point_path query
+// parameter which doesn't refer to a site's enforcement point. Every site
+// serves the same objects.
+func (s *Server) atEnforcementPoint(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if ep := r.URL.Query().Get("enforcement_point_path"); ep != "" {
+			s.mu.Lock()
+			_, ok := s.objects[ep]
+			s.mu.Unlock()
+			if !ok {
+				writeError(w, http.StatusNotFound, 500090, "The path="+ep+" is invalid.")
+				return
+			}
+		}
+		next(w, r)
+	}
+}
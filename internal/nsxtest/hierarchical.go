@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nsxtest
+
+import (
+	"maps"
+	"net/http"
+	"strings"
+
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+// hierarchicalType describes an object which can appear in an H-API
+// request wrapped in a Child<Type> object.
+type hierarchicalType struct {
+	// collection is the path segment the objects are stored below their
+	// parent.
+	collection string
+	// mustExist rejects requests which would create the object.
+	mustExist bool
+	validate  func(object) []client.APIError
+}
+
+var hierarchicalTypes = map[string]hierarchicalType{
+	"Segment":     {collection: "segments", mustExist: true},
+	"SegmentPort": {collection: "ports", validate: validateSegmentPort},
+}
+
+// patchInfra applies a Hierarchical Policy API request. Every change is
+// applied in a single transaction: if any child is invalid the store is
+// left unchanged.
+func (s *Server) patchInfra(w http.ResponseWriter, r *http.Request) {
+	obj, ok := decode(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved := maps.Clone(s.objects)
+	if apiErr := s.applyChildren(policyPath(r), obj); apiErr != nil {
+		s.objects = saved
+		writeError(w, http.StatusBadRequest, apiErr.ErrorCode, apiErr.ErrorMessage, apiErr.RelatedErrors...)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// applyChildren applies the Child<Type> wrappers below parent, which is
+// stored at parentPath. The caller must hold mu.
+func (s *Server) applyChildren(parentPath string, parent object) *client.APIError {
+	children, _ := parent["children"].([]any)
+	for _, c := range children {
+		child, _ := c.(map[string]any)
+		wrapper, _ := child["resource_type"].(string)
+		kind := strings.TrimPrefix(wrapper, "Child")
+		ht, known := hierarchicalTypes[kind]
+		obj, _ := child[kind].(map[string]any)
+		id, _ := obj["id"].(string)
+		if !known || obj == nil || id == "" {
+			return &client.APIError{ErrorCode: 500127, ErrorMessage: "Invalid child " + wrapper + " of " + parentPath + "."}
+		}
+
+		p := parentPath + "/" + ht.collection + "/" + id
+		if deleted, _ := child["marked_for_delete"].(bool); deleted {
+			for key := range s.objects {
+				if key == p || strings.HasPrefix(key, p+"/") {
+					delete(s.objects, key)
+				}
+			}
+			continue
+		}
+
+		existing, exists := s.objects[p]
+		if !exists && ht.mustExist {
+			return &client.APIError{ErrorCode: 500090, ErrorMessage: "The path=" + p + " is invalid."}
+		}
+
+		grandchildren := object{"children": obj["children"]}
+		merged := object{}
+		maps.Copy(merged, existing)
+		maps.Copy(merged, obj)
+		delete(merged, "children")
+		if ht.validate != nil {
+			if related := ht.validate(merged); len(related) > 0 {
+				return &client.APIError{ErrorCode: 500012, ErrorMessage: "Invalid " + kind + " " + p + ".", RelatedErrors: related}
+			}
+		}
+		s.store(p, merged, Username)
+
+		if apiErr := s.applyChildren(p, grandchildren); apiErr != nil {
+			return apiErr
+		}
+	}
+	return nil
+}
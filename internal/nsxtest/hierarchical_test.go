@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nsxtest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+func TestPatchInfra(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddSegment("parent-seg")
+	srv.AddSegment("child-seg")
+	srv.SetSegmentPort("child-seg", client.SegmentPort{Id: "stale"})
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	parent := client.SegmentPort{Id: "parent", Attachment: &client.PortAttachment{Id: "vif", Type: "PARENT"}}
+	child := client.SegmentPort{Id: "child", Attachment: &client.PortAttachment{ContextId: "vif", TrafficTag: 100, Type: "CHILD"}}
+
+	rsp, err := c.PatchInfra(ctx, client.NewInfra(
+		client.NewChildSegment("parent-seg", client.NewChildSegmentPort(parent)),
+		client.NewChildSegment("child-seg", client.NewChildSegmentPort(child), client.NewDeletedChildSegmentPort("stale")),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rsp.StatusCode)
+	}
+	if port, ok := srv.SegmentPort("parent-seg", "parent"); !ok || port.ResourceType != "SegmentPort" {
+		t.Fatalf("expected parent port to be created, got %+v", port)
+	}
+	if _, ok := srv.SegmentPort("child-seg", "child"); !ok {
+		t.Fatal("expected child port to be created")
+	}
+	if _, ok := srv.SegmentPort("child-seg", "stale"); ok {
+		t.Fatal("expected stale port to be deleted")
+	}
+
+	// An invalid child fails the whole transaction.
+	invalid := client.SegmentPort{Id: "invalid", Attachment: &client.PortAttachment{Type: "CHILD"}}
+	rsp, err = c.PatchInfra(ctx, client.NewInfra(
+		client.NewChildSegment("parent-seg", client.NewDeletedChildSegmentPort("parent")),
+		client.NewChildSegment("child-seg", client.NewChildSegmentPort(invalid)),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if apiErr := client.ParseAPIError(rsp); apiErr.StatusCode != http.StatusBadRequest || len(apiErr.RelatedErrors) != 2 {
+		t.Fatalf("expected 400 with 2 related errors, got %v", apiErr)
+	}
+	if _, ok := srv.SegmentPort("parent-seg", "parent"); !ok {
+		t.Fatal("expected a failed transaction to leave the parent port")
+	}
+}
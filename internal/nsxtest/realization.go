@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nsxtest
+
+import (
+	"net/http"
+)
+
+// realizedState overrides the realization of an object, which is otherwise
+// realized as soon as it is stored.
+type realizedState struct {
+	status string
+	alarms []string
+}
+
+// SetRealizedState makes the object at policyPath report status, such as
+// IN_PROGRESS or ERROR, with the given alarm messages until it is set
+// again. The state applies even if the object doesn't exist yet.
+func (s *Server) SetRealizedState(policyPath string, status string, alarms ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.realization[policyPath] = realizedState{status: status, alarms: alarms}
+}
+
+// realizedStateOf returns the realization of the object at intentPath. The
+// caller must hold mu.
+func (s *Server) realizedStateOf(intentPath string) (realizedState, bool) {
+	if _, ok := s.objects[intentPath]; !ok {
+		return realizedState{}, false
+	}
+	if state, ok := s.realization[intentPath]; ok {
+		return state, true
+	}
+	return realizedState{status: "SUCCESS"}, true
+}
+
+func (s *Server) realizedStatus(w http.ResponseWriter, r *http.Request) {
+	intentPath := r.URL.Query().Get("intent_path")
+
+	s.mu.Lock()
+	state, ok := s.realizedStateOf(intentPath)
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, 600, "The path="+intentPath+" is invalid.")
+		return
+	}
+	writeJSON(w, http.StatusOK, object{
+		"intent_path":    intentPath,
+		"publish_status": "REALIZED",
+		"consolidated_status": object{
+			"consolidated_status": state.status,
+		},
+	})
+}
+
+func (s *Server) realizedEntities(w http.ResponseWriter, r *http.Request) {
+	intentPath := r.URL.Query().Get("intent_path")
+
+	s.mu.Lock()
+	state, ok := s.realizedStateOf(intentPath)
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, 600, "The path="+intentPath+" is invalid.")
+		return
+	}
+	alarms := []object{}
+	for _, msg := range state.alarms {
+		alarms = append(alarms, object{"message": msg})
+	}
+	entity := object{
+		"id":             intentPath,
+		"entity_type":    "RealizedLogicalPort",
+		"state":          "REALIZED",
+		"runtime_status": state.status,
+		"alarms":         alarms,
+	}
+	if state.status == "ERROR" {
+		entity["state"] = "ERROR"
+	}
+	writeJSON(w, http.StatusOK, object{"result_count": 1, "results": []object{entity}})
+}
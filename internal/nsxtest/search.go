@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nsxtest
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// searchTerm matches objects whose field, a dotted path such as
+// attachment.traffic_tag, has a value matching pattern.
+type searchTerm struct {
+	field   string
+	pattern *regexp.Regexp
+}
+
+// parseSearchQuery parses the subset of the Lucene query syntax the server
+// supports: field:value terms joined by AND, where values may be quoted and
+// may contain * wildcards. Parentheses around terms are ignored.
+func parseSearchQuery(query string) ([]searchTerm, error) {
+	var terms []searchTerm
+	for _, clause := range strings.Split(query, " AND ") {
+		clause = strings.Trim(strings.TrimSpace(clause), "()")
+		field, value, ok := strings.Cut(clause, ":")
+		if !ok || field == "" || value == "" || strings.ContainsAny(field, " ") {
+			return nil, fmt.Errorf("unsupported search clause %q", clause)
+		}
+		value = strings.ReplaceAll(strings.Trim(value, `"`), `\`, "")
+		pattern := strings.ReplaceAll(regexp.QuoteMeta(value), `\*`, ".*")
+		terms = append(terms, searchTerm{
+			field:   field,
+			pattern: regexp.MustCompile("^(?i:" + pattern + ")$"),
+		})
+	}
+	return terms, nil
+}
+
+func (t searchTerm) matches(obj object) bool {
+	for _, value := range fieldValues(obj, strings.Split(t.field, ".")) {
+		if t.pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldValues returns the values at field in v, formatted as strings. Lists
+// contribute every element.
+func fieldValues(v any, field []string) []string {
+	switch v := v.(type) {
+	case []any:
+		var values []string
+		for _, element := range v {
+			values = append(values, fieldValues(element, field)...)
+		}
+		return values
+	case object:
+		if len(field) == 0 {
+			return nil
+		}
+		return fieldValues(v[field[0]], field[1:])
+	case map[string]any:
+		return fieldValues(object(v), field)
+	}
+	if len(field) > 0 || v == nil {
+		return nil
+	}
+	switch v := v.(type) {
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case string:
+		return []string{v}
+	}
+	return []string{fmt.Sprint(v)}
+}
+
+// search serves the search API. The default space is searched unless the
+// context query parameter names a project, as in projects:dev.
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	terms, err := parseSearchQuery(r.URL.Query().Get("query"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, 60506, "Invalid search query: "+err.Error())
+		return
+	}
+
+	root := "/infra/"
+	if strings.HasPrefix(r.URL.Path, globalManagerPrefix) {
+		root = "/global-infra/"
+	}
+	if project, ok := strings.CutPrefix(r.URL.Query().Get("context"), "projects:"); ok {
+		root = "/orgs/default/projects/" + project + "/"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var results []object
+	for p, obj := range s.objects {
+		if !strings.HasPrefix(p, root) {
+			continue
+		}
+		matched := true
+		for _, term := range terms {
+			if !term.matches(obj) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			results = append(results, obj)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i]["path"].(string) < results[j]["path"].(string)
+	})
+	s.writePage(w, r, results)
+}
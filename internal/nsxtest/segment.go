@@ -0,0 +1,163 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nsxtest
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+// Segment returns a segment as it is currently stored.
+func (s *Server) Segment(id string) (client.Segment, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var segment client.Segment
+	obj, ok := s.objects[client.PolicyContext{}.SegmentPath(id)]
+	if !ok {
+		return segment, false
+	}
+	return segment, convert(obj, &segment) == nil
+}
+
+// SetSegment creates or replaces a segment out-of-band, as another NSX user
+// would, bumping its revision.
+func (s *Server) SetSegment(segment client.Segment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var obj object
+	if err := convert(segment, &obj); err != nil {
+		panic(err)
+	}
+	s.store(client.PolicyContext{}.SegmentPath(segment.Id), obj, "other-user")
+}
+
+// patchObject merges the top level fields of the request body into the
+// object at the request's path, creating it if needed. The object's parent
+// must exist unless it is empty.
+func (s *Server) patchObject(w http.ResponseWriter, r *http.Request, parent string, resourceType string, validate func(object) []client.APIError) {
+	obj, ok := decode(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.objects[parent]; parent != "" && !ok {
+		writeError(w, http.StatusNotFound, 500090, "The path="+parent+" is invalid.")
+		return
+	}
+
+	p := policyPath(r)
+	merged := object{}
+	for k, v := range s.objects[p] {
+		merged[k] = v
+	}
+	for k, v := range obj {
+		merged[k] = v
+	}
+	if related := validate(merged); len(related) > 0 {
+		writeInvalid(w, resourceType, related)
+		return
+	}
+	s.store(p, merged, Username)
+	w.WriteHeader(http.StatusOK)
+}
+
+// putObject replaces the object at the request's path, checking the
+// revision of an existing object.
+func (s *Server) putObject(w http.ResponseWriter, r *http.Request, parent string, resourceType string, validate func(object) []client.APIError) {
+	obj, ok := decode(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.objects[parent]; parent != "" && !ok {
+		writeError(w, http.StatusNotFound, 500090, "The path="+parent+" is invalid.")
+		return
+	}
+
+	p := policyPath(r)
+	if existing, ok := s.objects[p]; ok {
+		if _, ok := obj["_revision"]; !ok {
+			writeError(w, http.StatusBadRequest, 500071, "The _revision property is required when updating an existing object.")
+			return
+		}
+		if revisionOf(obj) != revisionOf(existing) {
+			writeError(w, http.StatusPreconditionFailed, 604, "The object was modified by somebody else.")
+			return
+		}
+	}
+	if related := validate(obj); len(related) > 0 {
+		writeInvalid(w, resourceType, related)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.store(p, obj, Username))
+}
+
+func (s *Server) patchSegment(w http.ResponseWriter, r *http.Request) {
+	s.patchObject(w, r, "", "Segment", s.validateSegment)
+}
+
+func (s *Server) putSegment(w http.ResponseWriter, r *http.Request) {
+	s.putObject(w, r, "", "Segment", s.validateSegment)
+}
+
+// deleteSegment deletes a segment, unless it still has ports.
+func (s *Server) deleteSegment(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := policyPath(r)
+	if ports := s.children(p + "/ports"); len(ports) > 0 {
+		writeError(w, http.StatusBadRequest, 503060, "Segment "+p+" can not be deleted as it has segment ports.")
+		return
+	}
+	delete(s.objects, p)
+	w.WriteHeader(http.StatusOK)
+}
+
+// validateSegment applies a subset of NSX's segment validation. The caller
+// must hold mu.
+func (s *Server) validateSegment(obj object) []client.APIError {
+	invalid := func(message string) client.APIError {
+		return client.APIError{ErrorCode: 503040, ModuleName: "nsx-policy", ErrorMessage: message}
+	}
+
+	var related []client.APIError
+	if vlans, _ := obj["vlan_ids"].([]any); len(vlans) > 0 {
+		if tz, _ := obj["transport_zone_path"].(string); tz == "" {
+			related = append(related, invalid("transport_zone_path is required for VLAN backed segments."))
+		}
+	}
+	if connectivity, _ := obj["connectivity_path"].(string); connectivity != "" {
+		if _, ok := s.objects[connectivity]; !ok {
+			related = append(related, invalid("connectivity_path "+connectivity+" does not exist."))
+		}
+	}
+	subnets, _ := obj["subnets"].([]any)
+	for _, subnet := range subnets {
+		subnet, _ := subnet.(map[string]any)
+		gateway, _ := subnet["gateway_address"].(string)
+		ip, network, err := net.ParseCIDR(gateway)
+		if err != nil {
+			related = append(related, invalid("gateway_address "+gateway+" is not a valid CIDR."))
+			continue
+		}
+		if ip.Equal(network.IP) {
+			related = append(related, invalid("gateway_address "+gateway+" must not be the network address."))
+			continue
+		}
+		// NSX fills in the network of each subnet
+		subnet["network"] = network.String()
+	}
+	return related
+}
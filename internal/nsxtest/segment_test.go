@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nsxtest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+func TestSegmentLifecycle(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddTier1("t1")
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	segment := client.Segment{
+		DisplayName:      "web",
+		ResourceType:     "Segment",
+		ConnectivityPath: "/infra/tier-1s/t1",
+		Subnets:          []client.SegmentSubnet{{GatewayAddress: "10.0.1.1/24", DhcpRanges: []string{"10.0.1.100-10.0.1.200"}}},
+	}
+	rsp, err := c.PatchSegment(ctx, client.PatchSegmentRequest{SegmentId: "web", Segment: segment})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d", rsp.StatusCode)
+	}
+
+	rsp, err = c.GetSegment(ctx, "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got client.Segment
+	err = json.NewDecoder(rsp.Body).Decode(&got)
+	rsp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Path != "/infra/segments/web" || got.Subnets[0].Network != "10.0.1.0/24" || got.Revision == nil || *got.Revision != 0 {
+		t.Fatalf("unexpected segment %+v", got)
+	}
+
+	// Updates must carry the current revision.
+	got.AdminState = "DOWN"
+	rsp, err = c.UpdateSegment(ctx, client.UpdateSegmentRequest{SegmentId: "web", Segment: got})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d", rsp.StatusCode)
+	}
+	rsp, err = c.UpdateSegment(ctx, client.UpdateSegmentRequest{SegmentId: "web", Segment: got})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusPreconditionFailed {
+		t.Fatalf("expected a stale revision to be rejected, got %d", rsp.StatusCode)
+	}
+
+	// Fields the client doesn't model are kept when the update is merged
+	// onto the segment as read.
+	srv.SetObjectField("/infra/segments/web", "replication_mode", "SOURCE")
+	rsp, err = c.GetSegment(ctx, "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var current map[string]any
+	err = json.NewDecoder(rsp.Body).Decode(&current)
+	rsp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	revision := int64(2)
+	got.Revision = &revision
+	rsp, err = c.UpdateSegment(ctx, client.UpdateSegmentRequest{SegmentId: "web", Segment: got, Current: current})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d", rsp.StatusCode)
+	}
+	if mode, _ := srv.ObjectField("/infra/segments/web", "replication_mode"); mode != "SOURCE" {
+		t.Fatalf("expected replication_mode to be kept, got %v", mode)
+	}
+
+	// A segment with ports can't be deleted.
+	srv.SetSegmentPort("web", client.SegmentPort{Id: "p1"})
+	rsp, err = c.DeleteSegment(ctx, "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected a segment with ports not to be deleted, got %d", rsp.StatusCode)
+	}
+	srv.DeleteSegmentPort("web", "p1")
+	rsp, err = c.DeleteSegment(ctx, "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if _, ok := srv.Segment("web"); ok {
+		t.Fatal("expected the segment to be deleted")
+	}
+}
+
+func TestSegmentValidation(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+
+	rsp, err := c.PatchSegment(context.Background(), client.PatchSegmentRequest{SegmentId: "vlan", Segment: client.Segment{
+		ResourceType:     "Segment",
+		VlanIds:          []string{"100"},
+		ConnectivityPath: "/infra/tier-1s/missing",
+		Subnets:          []client.SegmentSubnet{{GatewayAddress: "10.0.1.0/24"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiErr := client.ParseAPIError(rsp)
+	if rsp.StatusCode != http.StatusBadRequest || len(apiErr.RelatedErrors) != 3 {
+		t.Fatalf("expected 3 related errors, got %d: %v", rsp.StatusCode, apiErr)
+	}
+}
+
+func TestListSegments(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.PageSize = 1
+	srv.AddSegment("web")
+	srv.AddSegment("db")
+	srv.SetSegmentPort("web", client.SegmentPort{Id: "p1"})
+	c := newClient(t, srv)
+
+	segments, err := c.ListAllSegments(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) != 2 || segments[0].Id != "db" || segments[1].Path != "/infra/segments/web" {
+		t.Fatalf("expected every segment across pages, without ports, got %+v", segments)
+	}
+}
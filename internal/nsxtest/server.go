@@ -0,0 +1,650 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package nsxtest provides an in-process fake of the parts of the NSX Policy
+// API used by the provider, so that the client and the provider's resources
+// can be tested without an NSX Manager.
+package nsxtest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+const (
+	// Username and Password are the credentials accepted by the server.
+	Username = "admin"
+	Password = "password"
+
+	policyPrefix        = "/policy/api/v1"
+	globalManagerPrefix = "/global-manager/api/v1"
+)
+
+// object is a stored policy object. Objects are kept as decoded JSON so
+// that fields the server doesn't know about round-trip unchanged.
+type object map[string]any
+
+// Fault is an error or delay injected into matching requests.
+type Fault struct {
+	// Method and PathPrefix select the requests affected. Empty values
+	// match every request.
+	Method     string
+	PathPrefix string
+
+	// Latency delays the response.
+	Latency time.Duration
+
+	// StatusCode, if set, fails the request with an NSX error envelope.
+	StatusCode int
+	// RetryAfter is sent as the Retry-After header of failed requests.
+	RetryAfter string
+
+	// Count is the number of requests affected. Zero affects every request
+	// until the fault is cleared.
+	Count int
+}
+
+// Server is a fake NSX Manager. Objects are stored in memory, keyed by their
+// policy path, with NSX's revision, pagination and error semantics.
+type Server struct {
+	*httptest.Server
+
+	// PageSize is the default number of results returned by list calls.
+	PageSize int
+
+	// RejectAttachedPortDeletes fails deletes of segment ports which still
+	// have an attachment, as NSX does while their VIF is connected.
+	RejectAttachedPortDeletes bool
+
+	mu          sync.Mutex
+	objects     map[string]object
+	sessions    map[string]string
+	faults      []*Fault
+	requests    []string
+	realization map[string]realizedState
+
+	// vms and vifs are the fabric inventory, which isn't policy intent.
+	vms  []object
+	vifs []object
+}
+
+// NewServer starts a fake NSX Manager. Callers must Close it when done.
+func NewServer() *Server {
+	return newServer(httptest.NewServer)
+}
+
+// NewTLSServer starts a fake NSX Manager which serves HTTPS with a self-signed
+// certificate, available from its Certificate method. Callers must Close it
+// when done.
+func NewTLSServer() *Server {
+	return newServer(httptest.NewTLSServer)
+}
+
+func newServer(start func(http.Handler) *httptest.Server) *Server {
+	s := &Server{
+		PageSize: 1000,
+		objects:  map[string]object{},
+		sessions: map[string]string{},
+
+		realization: map[string]realizedState{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/session/create", s.createSession)
+	mux.HandleFunc("POST /api/session/destroy", s.destroySession)
+	mux.HandleFunc("GET /api/v1/reverse-proxy/node/health", s.authenticated(s.nodeHealth))
+	mux.HandleFunc("GET /api/v1/fabric/virtual-machines", s.authenticated(s.listVirtualMachines))
+	mux.HandleFunc("GET /api/v1/fabric/vifs", s.authenticated(s.listVifs))
+	for _, infra := range []string{
+		"/policy/api/v1/infra",
+		"/policy/api/v1/orgs/{org}/projects/{project}/infra",
+		"/global-manager/api/v1/global-infra",
+	} {
+		mux.HandleFunc("PATCH "+infra, s.authenticated(s.patchInfra))
+	}
+	for _, root := range []string{
+		"/policy/api/v1/infra",
+		"/policy/api/v1/orgs/{org}/projects/{project}/infra",
+		"/policy/api/v1/orgs/{org}/projects/{project}/vpcs/{vpc}",
+		"/global-manager/api/v1/global-infra",
+	} {
+		mux.HandleFunc("GET "+root+"/realized-state/status", s.authenticated(s.realizedStatus))
+		mux.HandleFunc("GET "+root+"/realized-state/realized-entities", s.authenticated(s.realizedEntities))
+	}
+	for _, segments := range []string{
+		"/policy/api/v1/infra/segments",
+		"/policy/api/v1/orgs/{org}/projects/{project}/infra/segments",
+		"/policy/api/v1/orgs/{org}/projects/{project}/vpcs/{vpc}/subnets",
+		"/global-manager/api/v1/global-infra/segments",
+	} {
+		segment := segments + "/{segment}"
+		mux.HandleFunc("GET "+segments, s.authenticated(s.atEnforcementPoint(s.listObjects)))
+		mux.HandleFunc("GET "+segment, s.authenticated(s.atEnforcementPoint(s.getObject)))
+		mux.HandleFunc("PATCH "+segment, s.authenticated(s.patchSegment))
+		mux.HandleFunc("PUT "+segment, s.authenticated(s.putSegment))
+		mux.HandleFunc("DELETE "+segment, s.authenticated(s.deleteSegment))
+		mux.HandleFunc("GET "+segment+"/ports", s.authenticated(s.atEnforcementPoint(s.listSegmentPorts)))
+		mux.HandleFunc("GET "+segment+"/ports/{port}", s.authenticated(s.atEnforcementPoint(s.getObject)))
+		mux.HandleFunc("PATCH "+segment+"/ports/{port}", s.authenticated(s.patchSegmentPort))
+		mux.HandleFunc("PUT "+segment+"/ports/{port}", s.authenticated(s.putSegmentPort))
+		mux.HandleFunc("DELETE "+segment+"/ports/{port}", s.authenticated(s.deleteObject))
+	}
+	for _, infra := range []string{
+		"/policy/api/v1/infra",
+		"/policy/api/v1/orgs/{org}/projects/{project}/infra",
+		"/global-manager/api/v1/global-infra",
+	} {
+		mux.HandleFunc("GET "+infra+"/sites/{site}/enforcement-points/{ep}/transport-zones", s.authenticated(s.listObjects))
+	}
+	for _, tier1 := range []string{
+		"/policy/api/v1/infra/tier-1s/{tier1}",
+		"/policy/api/v1/orgs/{org}/projects/{project}/infra/tier-1s/{tier1}",
+		"/global-manager/api/v1/global-infra/tier-1s/{tier1}",
+	} {
+		iface := tier1 + "/locale-services/{ls}/interfaces/{interface}"
+		mux.HandleFunc("GET "+iface, s.authenticated(s.getObject))
+		mux.HandleFunc("PATCH "+iface, s.authenticated(s.patchTier1Interface))
+		mux.HandleFunc("PUT "+iface, s.authenticated(s.putTier1Interface))
+		mux.HandleFunc("DELETE "+iface, s.authenticated(s.deleteObject))
+		route := tier1 + "/static-routes/{route}"
+		mux.HandleFunc("GET "+route, s.authenticated(s.getObject))
+		mux.HandleFunc("PATCH "+route, s.authenticated(s.patchStaticRoute))
+		mux.HandleFunc("PUT "+route, s.authenticated(s.putStaticRoute))
+		mux.HandleFunc("DELETE "+route, s.authenticated(s.deleteObject))
+	}
+	for _, api := range []string{policyPrefix, globalManagerPrefix} {
+		mux.HandleFunc("GET "+api+"/search/query", s.authenticated(s.search))
+	}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, 404, "The requested URI: "+r.URL.Path+" could not be found.")
+	})
+
+	s.Server = start(s.injectFaults(mux))
+	return s
+}
+
+// InjectFault adds a fault. Faults are matched in the order they were added.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes every injected fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// Requests returns the "METHOD path" of every request received so far.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+// AddSegment creates an empty segment which ports can be attached to.
+func (s *Server) AddSegment(id string) {
+	s.AddSegmentAt(client.PolicyContext{}, id)
+}
+
+// AddSegmentAt creates an empty segment, or a VPC subnet, in a project or
+// VPC.
+func (s *Server) AddSegmentAt(pc client.PolicyContext, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.store(pc.SegmentPath(id), object{
+		"id":            id,
+		"display_name":  id,
+		"resource_type": "Segment",
+	}, "admin")
+}
+
+// SegmentPort returns a port as it is currently stored.
+func (s *Server) SegmentPort(segment_id string, port_id string) (client.SegmentPort, bool) {
+	return s.SegmentPortAt(client.PolicyContext{}, segment_id, port_id)
+}
+
+// SegmentPortAt returns a port of a project or VPC as it is currently
+// stored.
+func (s *Server) SegmentPortAt(pc client.PolicyContext, segment_id string, port_id string) (client.SegmentPort, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var port client.SegmentPort
+	obj, ok := s.objects[pc.SegmentPortPath(segment_id, port_id)]
+	if !ok {
+		return port, false
+	}
+	return port, convert(obj, &port) == nil
+}
+
+// SetSegmentPort creates or replaces a port out-of-band, as another NSX
+// user would, bumping its revision.
+func (s *Server) SetSegmentPort(segment_id string, port client.SegmentPort) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var obj object
+	if err := convert(port, &obj); err != nil {
+		panic(err)
+	}
+	s.store(client.PolicyContext{}.SegmentPortPath(segment_id, port.Id), obj, "other-user")
+}
+
+// DeleteSegmentPort removes a port out-of-band.
+func (s *Server) DeleteSegmentPort(segment_id string, port_id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.objects, client.PolicyContext{}.SegmentPortPath(segment_id, port_id))
+}
+
+// ObjectField returns a field of the object at policyPath as it is
+// currently stored, such as one the client doesn't model.
+func (s *Server) ObjectField(policyPath string, field string) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.objects[policyPath][field]
+	return value, ok
+}
+
+// SetObjectField sets a field of the object at policyPath out-of-band, as
+// another NSX user would, bumping its revision.
+func (s *Server) SetObjectField(policyPath string, field string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.objects[policyPath]
+	if !ok {
+		panic("no object at " + policyPath)
+	}
+	obj := maps.Clone(existing)
+	obj[field] = value
+	s.store(policyPath, obj, "other-user")
+}
+
+// policyPath returns the policy path of the object a request refers to.
+func policyPath(r *http.Request) string {
+	p := path.Clean(r.URL.Path)
+	if strings.HasPrefix(p, globalManagerPrefix) {
+		return strings.TrimPrefix(p, globalManagerPrefix)
+	}
+	return strings.TrimPrefix(p, policyPrefix)
+}
+
+// store saves obj at policyPath, filling in the system-owned fields and
+// incrementing the revision of any existing object. The caller must hold mu.
+func (s *Server) store(policyPath string, obj object, user string) object {
+	now := time.Now().UnixMilli()
+	revision := 0
+	createUser, createTime, id := any(user), any(now), any(uniqueID())
+	if existing, ok := s.objects[policyPath]; ok {
+		revision = revisionOf(existing) + 1
+		createUser, createTime, id = existing["_create_user"], existing["_create_time"], existing["unique_id"]
+	}
+
+	obj["id"] = path.Base(policyPath)
+	obj["path"] = policyPath
+	obj["parent_path"] = path.Dir(path.Dir(policyPath))
+	obj["unique_id"] = id
+	if obj["resource_type"] == "SegmentPort" {
+		// NSX realizes a segment port as a logical port with the same id
+		obj["realization_id"] = id
+	}
+	obj["_revision"] = revision
+	obj["_create_user"] = createUser
+	obj["_create_time"] = createTime
+	obj["_last_modified_user"] = user
+	obj["_last_modified_time"] = now
+	obj["_system_owned"] = false
+	s.objects[policyPath] = obj
+	return obj
+}
+
+// children returns the objects directly below parent, sorted by id. The
+// caller must hold mu.
+func (s *Server) children(parent string) []object {
+	var results []object
+	for p, obj := range s.objects {
+		if path.Dir(p) == parent {
+			results = append(results, obj)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i]["id"].(string) < results[j]["id"].(string)
+	})
+	return results
+}
+
+func revisionOf(obj object) int {
+	switch v := obj["_revision"].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+func uniqueID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:4]) + "-" + hex.EncodeToString(b[4:6]) + "-" +
+		hex.EncodeToString(b[6:8]) + "-" + hex.EncodeToString(b[8:10]) + "-" + hex.EncodeToString(b[10:])
+}
+
+// convert copies between types via their JSON representation.
+func convert(from any, to any) error {
+	buf, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, to)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an NSX error envelope.
+func writeError(w http.ResponseWriter, status int, code int, message string, related ...client.APIError) {
+	writeJSON(w, status, client.APIError{
+		HttpStatus:    strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_")),
+		ErrorCode:     code,
+		ErrorMessage:  message,
+		ModuleName:    "nsx-policy",
+		RelatedErrors: related,
+	})
+}
+
+func (s *Server) injectFaults(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		var fault Fault
+		for i, f := range s.faults {
+			if (f.Method == "" || f.Method == r.Method) && strings.HasPrefix(r.URL.Path, f.PathPrefix) {
+				fault = *f
+				if f.Count > 0 {
+					if f.Count--; f.Count == 0 {
+						s.faults = append(s.faults[:i], s.faults[i+1:]...)
+					}
+				}
+				break
+			}
+		}
+		s.mu.Unlock()
+
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault.StatusCode != 0 {
+			if fault.RetryAfter != "" {
+				w.Header().Set("Retry-After", fault.RetryAfter)
+			}
+			writeError(w, fault.StatusCode, fault.StatusCode, "Injected fault: "+http.StatusText(fault.StatusCode))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) createSession(w http.ResponseWriter, r *http.Request) {
+	if r.FormValue("j_username") != Username || r.FormValue("j_password") != Password {
+		writeError(w, http.StatusForbidden, 403, "Username/password combination is incorrect or user account is locked.")
+		return
+	}
+
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	session, token := hex.EncodeToString(b[:8]), hex.EncodeToString(b[8:])
+
+	s.mu.Lock()
+	s.sessions[session] = token
+	s.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: session, Path: "/", HttpOnly: true})
+	w.Header().Set("X-XSRF-TOKEN", token)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) destroySession(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie("JSESSIONID"); err == nil {
+		s.mu.Lock()
+		delete(s.sessions, cookie.Value)
+		s.mu.Unlock()
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// nodeHealth reports the node as healthy. Inject a fault to make it
+// unhealthy.
+func (s *Server) nodeHealth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, object{"healthy": true})
+}
+
+// ExpireSessions invalidates every session, as NSX does when they time out.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions = map[string]string{}
+}
+
+// authenticated rejects requests without a valid session cookie and XSRF
+// token.
+func (s *Server) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("JSESSIONID")
+		if err == nil {
+			s.mu.Lock()
+			token, ok := s.sessions[cookie.Value]
+			s.mu.Unlock()
+			if ok && token == r.Header.Get("X-XSRF-TOKEN") {
+				next(w, r)
+				return
+			}
+		}
+		writeError(w, http.StatusForbidden, 98, "The credentials were incorrect or the account specified has been locked.")
+	}
+}
+
+func (s *Server) getObject(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.objects[policyPath(r)]
+	if !ok {
+		writeError(w, http.StatusNotFound, 500090, "The path="+policyPath(r)+" is invalid.")
+		return
+	}
+	writeJSON(w, http.StatusOK, obj)
+}
+
+// listObjects writes a page of the objects below the request's path.
+func (s *Server) listObjects(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.list(w, r, policyPath(r))
+}
+
+func (s *Server) deleteObject(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := policyPath(r)
+	if s.RejectAttachedPortDeletes {
+		if attachment, ok := s.objects[p]["attachment"]; ok && attachment != nil {
+			writeError(w, http.StatusBadRequest, 503042, "Segment port "+p+" can not be deleted as it has an attachment.")
+			return
+		}
+	}
+
+	// NSX treats deleting a policy object which doesn't exist as a success
+	delete(s.objects, p)
+	w.WriteHeader(http.StatusOK)
+}
+
+// list writes a page of the objects below parent, honouring the cursor and
+// page_size query parameters. The caller must hold mu.
+func (s *Server) list(w http.ResponseWriter, r *http.Request, parent string) {
+	s.writePage(w, r, s.children(parent))
+}
+
+// writePage writes the page of results selected by the cursor and page_size
+// query parameters.
+func (s *Server) writePage(w http.ResponseWriter, r *http.Request, results []object) {
+	start, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = s.PageSize
+	}
+	end := min(start+pageSize, len(results))
+	start = min(start, end)
+
+	body := map[string]any{
+		"result_count": len(results),
+		"results":      append([]object{}, results[start:end]...),
+	}
+	if end < len(results) {
+		body["cursor"] = strconv.Itoa(end)
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+// decode reads the request body as an object, writing an error if it is
+// invalid.
+func decode(w http.ResponseWriter, r *http.Request) (object, bool) {
+	var obj object
+	if err := json.NewDecoder(r.Body).Decode(&obj); err != nil {
+		writeError(w, http.StatusBadRequest, 255, "Invalid JSON in request body: "+err.Error())
+		return nil, false
+	}
+	return obj, true
+}
+
+func (s *Server) listSegmentPorts(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	segment := path.Dir(policyPath(r))
+	if _, ok := s.objects[segment]; !ok {
+		writeError(w, http.StatusNotFound, 500090, "The path="+segment+" is invalid.")
+		return
+	}
+	s.list(w, r, policyPath(r))
+}
+
+func (s *Server) patchSegmentPort(w http.ResponseWriter, r *http.Request) {
+	obj, ok := decode(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := policyPath(r)
+	if _, ok := s.objects[path.Dir(path.Dir(p))]; !ok {
+		writeError(w, http.StatusNotFound, 500090, "The path="+path.Dir(path.Dir(p))+" is invalid.")
+		return
+	}
+
+	// PATCH merges the top level fields into any existing port
+	merged := object{}
+	for k, v := range s.objects[p] {
+		merged[k] = v
+	}
+	for k, v := range obj {
+		merged[k] = v
+	}
+	if related := validateSegmentPort(merged); len(related) > 0 {
+		writeInvalid(w, "SegmentPort", related)
+		return
+	}
+	s.store(p, merged, Username)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) putSegmentPort(w http.ResponseWriter, r *http.Request) {
+	obj, ok := decode(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := policyPath(r)
+	if _, ok := s.objects[path.Dir(path.Dir(p))]; !ok {
+		writeError(w, http.StatusNotFound, 500090, "The path="+path.Dir(path.Dir(p))+" is invalid.")
+		return
+	}
+	if existing, ok := s.objects[p]; ok {
+		if _, ok := obj["_revision"]; !ok {
+			writeError(w, http.StatusBadRequest, 500071, "The _revision property is required when updating an existing object.")
+			return
+		}
+		if revisionOf(obj) != revisionOf(existing) {
+			writeError(w, http.StatusPreconditionFailed, 604, "The object was modified by somebody else.")
+			return
+		}
+	}
+	if related := validateSegmentPort(obj); len(related) > 0 {
+		writeInvalid(w, "SegmentPort", related)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.store(p, obj, Username))
+}
+
+// validateSegmentPort applies a subset of NSX's segment port validation,
+// returning each problem as a related error.
+func validateSegmentPort(obj object) []client.APIError {
+	var related []client.APIError
+	attachment, _ := obj["attachment"].(map[string]any)
+	switch attachment["type"] {
+	case "PARENT":
+		if id, _ := attachment["id"].(string); id == "" {
+			related = append(related, client.APIError{ErrorCode: 503040, ModuleName: "nsx-policy", ErrorMessage: "attachment.id is required for PARENT ports."})
+		}
+	case "CHILD":
+		if id, _ := attachment["context_id"].(string); id == "" {
+			related = append(related, client.APIError{ErrorCode: 503040, ModuleName: "nsx-policy", ErrorMessage: "attachment.context_id is required for CHILD ports."})
+		}
+		if tag := attachment["traffic_tag"]; tag == nil || tag == "" {
+			related = append(related, client.APIError{ErrorCode: 503040, ModuleName: "nsx-policy", ErrorMessage: "attachment.traffic_tag is required for CHILD ports."})
+		}
+	}
+	return related
+}
+
+// writeInvalid writes the error for an object which failed validation.
+func writeInvalid(w http.ResponseWriter, resourceType string, related []client.APIError) {
+	writeError(w, http.StatusBadRequest, 500012, "Invalid "+resourceType+".", related...)
+}
@@ -0,0 +1,380 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nsxtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+func newClient(t *testing.T, srv *Server, opts ...client.ClientOption) *client.Client {
+	t.Helper()
+	c, err := client.NewClient(srv.URL, Username, Password, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// getSegmentPort returns nil if the port doesn't exist.
+func getSegmentPort(t *testing.T, c *client.Client, segment_id string, port_id string) *client.SegmentPort {
+	t.Helper()
+	rsp, err := c.GetSegmentPort(context.Background(), segment_id, port_id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d", rsp.StatusCode)
+	}
+	var port client.SegmentPort
+	if err := json.NewDecoder(rsp.Body).Decode(&port); err != nil {
+		t.Fatal(err)
+	}
+	return &port
+}
+
+func TestSegmentPortLifecycle(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddSegment("seg")
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	port := client.SegmentPort{
+		DisplayName: "parent",
+		Attachment:  &client.PortAttachment{Id: "vif-1", Type: "PARENT"},
+	}
+	rsp, err := c.PatchSegmentPort(ctx, client.PatchSegmentPortRequest{SegmentId: "seg", PortId: "p1", SegmentPort: port})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rsp.StatusCode)
+	}
+
+	got := getSegmentPort(t, c, "seg", "p1")
+	if got == nil {
+		t.Fatal("expected port to exist")
+	}
+	if got.Id != "p1" || got.DisplayName != "parent" || got.Revision == nil || *got.Revision != 0 {
+		t.Fatalf("unexpected port %+v", got)
+	}
+
+	// A PUT with a stale revision is rejected.
+	srv.SetSegmentPort("seg", *got)
+	rsp, err = c.UpdateSegmentPort(ctx, client.UpdateSegmentPortRequest{SegmentId: "seg", PortId: "p1", SegmentPort: *got})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412, got %d", rsp.StatusCode)
+	}
+
+	// Fields the client doesn't model are kept when the update is merged
+	// onto the port as read.
+	srv.SetObjectField("/infra/segments/seg/ports/p1", "origin_id", "vm-42")
+	rsp, err = c.GetSegmentPort(ctx, "seg", "p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var current map[string]any
+	err = json.NewDecoder(rsp.Body).Decode(&current)
+	rsp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	revision := int64(2)
+	got.Revision = &revision
+	rsp, err = c.UpdateSegmentPort(ctx, client.UpdateSegmentPortRequest{SegmentId: "seg", PortId: "p1", SegmentPort: *got, Current: current})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rsp.StatusCode)
+	}
+	if origin, _ := srv.ObjectField("/infra/segments/seg/ports/p1", "origin_id"); origin != "vm-42" {
+		t.Fatalf("expected origin_id to be kept, got %v", origin)
+	}
+
+	rsp, err = c.DeleteSegmentPort(ctx, "seg", "p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if _, ok := srv.SegmentPort("seg", "p1"); ok {
+		t.Fatal("expected port to be deleted")
+	}
+	if getSegmentPort(t, c, "seg", "p1") != nil {
+		t.Fatal("expected a deleted port to return 404")
+	}
+}
+
+func TestSegmentPortValidation(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddSegment("seg")
+	c := newClient(t, srv)
+
+	rsp, err := c.PatchSegmentPort(context.Background(), client.PatchSegmentPortRequest{
+		SegmentId:   "seg",
+		PortId:      "child",
+		SegmentPort: client.SegmentPort{Attachment: &client.PortAttachment{Type: "CHILD"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiErr := client.ParseAPIError(rsp)
+	if apiErr.StatusCode != http.StatusBadRequest || len(apiErr.RelatedErrors) != 2 {
+		t.Fatalf("expected 400 with 2 related errors, got %v", apiErr)
+	}
+}
+
+func TestSegmentPortPagination(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddSegment("seg")
+	srv.PageSize = 2
+	for i := range 5 {
+		srv.SetSegmentPort("seg", client.SegmentPort{Id: fmt.Sprintf("port-%d", i)})
+	}
+	c := newClient(t, srv)
+
+	ports, err := c.ListAllSegmentPorts(context.Background(), "seg", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ports) != 5 {
+		t.Fatalf("expected 5 ports, got %d", len(ports))
+	}
+	if _, err := c.ListAllSegmentPorts(context.Background(), "missing", nil); err == nil {
+		t.Fatal("expected an error listing the ports of a missing segment")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddSegment("seg-a")
+	srv.AddSegment("seg-b")
+	srv.PageSize = 1
+	for segment, ports := range map[string][]client.SegmentPort{
+		"seg-a": {
+			{Id: "web-1", DisplayName: "web-1", Attachment: &client.PortAttachment{Type: "CHILD", TrafficTag: 1001}},
+			{Id: "db-1", DisplayName: "db-1", Attachment: &client.PortAttachment{Type: "CHILD", TrafficTag: 1002}},
+		},
+		"seg-b": {
+			{Id: "web-2", DisplayName: "web-2", Attachment: &client.PortAttachment{Type: "CHILD", TrafficTag: 1001}},
+		},
+	} {
+		for _, port := range ports {
+			port.ResourceType = "SegmentPort"
+			srv.SetSegmentPort(segment, port)
+		}
+	}
+	c := newClient(t, srv)
+
+	for query, expected := range map[string][]string{
+		"attachment.traffic_tag:1001":        {"web-1", "web-2"},
+		`display_name:db*`:                   {"db-1"},
+		"id:web-2 AND attachment.type:CHILD": {"web-2"},
+		"display_name:missing":               nil,
+	} {
+		ports, err := c.SearchSegmentPorts(context.Background(), query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, port := range ports {
+			ids = append(ids, port.Id)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(expected) {
+			t.Errorf("%s: expected %v, got %v", query, expected, ids)
+		}
+	}
+
+	if _, err := c.SearchSegmentPorts(context.Background(), "display_name OR id", nil); err == nil {
+		t.Fatal("expected an error for an unsupported query")
+	}
+}
+
+func TestFaults(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddSegment("seg")
+	srv.InjectFault(Fault{Method: http.MethodGet, PathPrefix: "/policy/", StatusCode: http.StatusTooManyRequests, RetryAfter: "0", Count: 2})
+	c := newClient(t, srv, client.WithRetryPolicy(client.RetryPolicy{
+		MaxRetries:           3,
+		MinDelay:             time.Millisecond,
+		MaxDelay:             time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusTooManyRequests},
+	}))
+
+	if _, err := c.ListAllSegmentPorts(context.Background(), "seg", nil); err != nil {
+		t.Fatal(err)
+	}
+	lists := 0
+	for _, req := range srv.Requests() {
+		if req == "GET /policy/api/v1/infra/segments/seg/ports" {
+			lists++
+		}
+	}
+	if lists != 3 {
+		t.Fatalf("expected 3 attempts, got %d", lists)
+	}
+
+	srv.InjectFault(Fault{Latency: time.Second})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.ListAllSegmentPorts(ctx, "seg", nil); err == nil {
+		t.Fatal("expected the request to time out")
+	}
+}
+
+func TestExpiredSession(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddSegment("seg")
+	c := newClient(t, srv)
+
+	if _, err := c.ListAllSegmentPorts(context.Background(), "seg", nil); err != nil {
+		t.Fatal(err)
+	}
+	srv.ExpireSessions()
+	if _, err := c.ListAllSegmentPorts(context.Background(), "seg", nil); err != nil {
+		t.Fatalf("expected the session to be renewed: %v", err)
+	}
+}
+
+func TestPolicyContext(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	project := client.PolicyContext{ProjectId: "dev"}
+	vpc := client.PolicyContext{ProjectId: "dev", VpcId: "web"}
+	srv.AddSegmentAt(project, "seg")
+	srv.AddSegmentAt(vpc, "subnet")
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	for _, pc := range []client.PolicyContext{project, vpc} {
+		segment_id := "seg"
+		if pc.IsVpc() {
+			segment_id = "subnet"
+		}
+		rsp, err := c.InContext(pc).PatchSegmentPort(ctx, client.PatchSegmentPortRequest{SegmentId: segment_id, PortId: "port"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsp.Body.Close()
+		if rsp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200 in %+v, got %d", pc, rsp.StatusCode)
+		}
+		if _, ok := srv.SegmentPortAt(pc, segment_id, "port"); !ok {
+			t.Fatalf("expected port to be created in %+v", pc)
+		}
+	}
+
+	// The default space is unaffected.
+	if _, err := c.ListAllSegmentPorts(ctx, "seg", nil); err == nil {
+		t.Fatal("expected the segment not to exist outside the project")
+	}
+}
+
+func TestDetachSegmentPort(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddSegment("seg")
+	srv.RejectAttachedPortDeletes = true
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	port := client.SegmentPort{
+		DisplayName: "parent",
+		Attachment:  &client.PortAttachment{Id: "vif-1", Type: "PARENT"},
+	}
+	rsp, err := c.PatchSegmentPort(ctx, client.PatchSegmentPortRequest{SegmentId: "seg", PortId: "p1", SegmentPort: port})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+
+	rsp, err = c.DeleteSegmentPort(ctx, "seg", "p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected deleting an attached port to fail with 400, got %d", rsp.StatusCode)
+	}
+
+	if err := c.DetachSegmentPort(ctx, "seg", "p1"); err != nil {
+		t.Fatal(err)
+	}
+	got := getSegmentPort(t, c, "seg", "p1")
+	if got == nil || got.Attachment != nil || got.DisplayName != "parent" {
+		t.Fatalf("expected only the attachment to be removed, got %+v", got)
+	}
+
+	rsp, err = c.DeleteSegmentPort(ctx, "seg", "p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rsp.StatusCode)
+	}
+	if err := c.WaitForSegmentPortDeletion(ctx, "seg", "p1", time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	// Detaching a port which doesn't exist is a no-op.
+	if err := c.DetachSegmentPort(ctx, "seg", "p1"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGlobalManager(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	global := client.PolicyContext{GlobalManager: true}
+	srv.AddSegmentAt(global, "stretched")
+	srv.AddSite("paris")
+	c := newClient(t, srv, client.WithPolicyContext(global))
+	ctx := context.Background()
+
+	rsp, err := c.PatchSegmentPort(ctx, client.PatchSegmentPortRequest{SegmentId: "stretched", PortId: "port"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rsp.StatusCode)
+	}
+	if _, ok := srv.SegmentPortAt(global, "stretched", "port"); !ok {
+		t.Fatal("expected port to be created in the global infra")
+	}
+
+	ports, err := c.ListAllSegmentPorts(ctx, "stretched", nil, client.WithEnforcementPoint(client.EnforcementPointPath("paris", "")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ports) != 1 {
+		t.Fatalf("expected 1 port from the paris Local Manager, got %d", len(ports))
+	}
+	if _, err := c.ListAllSegmentPorts(ctx, "stretched", nil, client.WithEnforcementPoint(client.EnforcementPointPath("london", ""))); err == nil {
+		t.Fatal("expected reads from an unknown site to fail")
+	}
+}
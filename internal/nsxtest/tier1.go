@@ -0,0 +1,181 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nsxtest
+
+import (
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+// DefaultLocaleServiceId is the locale service AddTier1 creates, as the NSX
+// UI does for every tier-1 gateway.
+const DefaultLocaleServiceId = "default"
+
+// AddTier1 adds a tier-1 gateway with a default locale service, which
+// segments can be connected to and service interfaces added to.
+func (s *Server) AddTier1(id string) {
+	s.AddTier1At(client.PolicyContext{}, id)
+}
+
+// AddTier1At adds a tier-1 gateway with a default locale service to a
+// project.
+func (s *Server) AddTier1At(pc client.PolicyContext, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tier1 := pc.Tier1Path(id)
+	s.store(tier1, object{
+		"id":            id,
+		"display_name":  id,
+		"resource_type": "Tier1",
+	}, "admin")
+	s.store(tier1+"/locale-services/"+DefaultLocaleServiceId, object{
+		"id":            DefaultLocaleServiceId,
+		"display_name":  DefaultLocaleServiceId,
+		"resource_type": "LocaleServices",
+	}, "admin")
+}
+
+// Tier1Interface returns a service interface as it is currently stored.
+func (s *Server) Tier1Interface(tier1_id string, locale_service_id string, interface_id string) (client.Tier1Interface, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var iface client.Tier1Interface
+	obj, ok := s.objects[client.PolicyContext{}.Tier1InterfacePath(tier1_id, locale_service_id, interface_id)]
+	if !ok {
+		return iface, false
+	}
+	return iface, convert(obj, &iface) == nil
+}
+
+// patchTier1Interface creates or updates a service interface of an existing
+// locale service.
+func (s *Server) patchTier1Interface(w http.ResponseWriter, r *http.Request) {
+	s.patchObject(w, r, path.Dir(path.Dir(policyPath(r))), "Tier1Interface", s.validateTier1Interface)
+}
+
+func (s *Server) putTier1Interface(w http.ResponseWriter, r *http.Request) {
+	s.putObject(w, r, path.Dir(path.Dir(policyPath(r))), "Tier1Interface", s.validateTier1Interface)
+}
+
+// validateTier1Interface applies a subset of NSX's service interface
+// validation, and defaults urpf_mode to STRICT. The caller must hold mu.
+func (s *Server) validateTier1Interface(obj object) []client.APIError {
+	invalid := func(message string) client.APIError {
+		return client.APIError{ErrorCode: 503040, ModuleName: "nsx-policy", ErrorMessage: message}
+	}
+
+	var related []client.APIError
+	segmentPath, _ := obj["segment_path"].(string)
+	if segment, ok := s.objects[segmentPath]; !ok {
+		related = append(related, invalid("segment_path "+segmentPath+" does not exist."))
+	} else if connectivity, _ := segment["connectivity_path"].(string); connectivity != "" {
+		related = append(related, invalid("segment_path "+segmentPath+" is connected to "+connectivity+" and can't have a service interface."))
+	}
+
+	subnets, _ := obj["subnets"].([]any)
+	if len(subnets) == 0 {
+		related = append(related, invalid("subnets must not be empty."))
+	}
+	for _, subnet := range subnets {
+		subnet, _ := subnet.(map[string]any)
+		prefixLen, _ := subnet["prefix_len"].(float64)
+		addresses, _ := subnet["ip_addresses"].([]any)
+		if len(addresses) == 0 {
+			related = append(related, invalid("ip_addresses must not be empty."))
+		}
+		for _, address := range addresses {
+			address, _ := address.(string)
+			ip := net.ParseIP(address)
+			if ip == nil {
+				related = append(related, invalid("ip_addresses "+address+" is not a valid IP address."))
+				continue
+			}
+			bits := 128
+			if ip.To4() != nil {
+				bits = 32
+			}
+			if prefixLen < 1 || int(prefixLen) > bits {
+				related = append(related, invalid("prefix_len "+strconv.Itoa(int(prefixLen))+" is not valid for "+address+"."))
+			}
+		}
+	}
+
+	if mtu, ok := obj["mtu"].(float64); ok && mtu < 64 {
+		related = append(related, invalid("mtu must be at least 64."))
+	}
+	switch mode, _ := obj["urpf_mode"].(string); mode {
+	case "":
+		obj["urpf_mode"] = "STRICT"
+	case "STRICT", "NONE":
+	default:
+		related = append(related, invalid("urpf_mode "+mode+" is not one of STRICT, NONE."))
+	}
+	return related
+}
+
+// StaticRoute returns a static route as it is currently stored.
+func (s *Server) StaticRoute(tier1_id string, route_id string) (client.StaticRoute, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var route client.StaticRoute
+	obj, ok := s.objects[client.PolicyContext{}.StaticRoutePath(tier1_id, route_id)]
+	if !ok {
+		return route, false
+	}
+	return route, convert(obj, &route) == nil
+}
+
+// patchStaticRoute creates or updates a static route of an existing tier-1
+// gateway.
+func (s *Server) patchStaticRoute(w http.ResponseWriter, r *http.Request) {
+	s.patchObject(w, r, path.Dir(path.Dir(policyPath(r))), "StaticRoutes", validateStaticRoute)
+}
+
+func (s *Server) putStaticRoute(w http.ResponseWriter, r *http.Request) {
+	s.putObject(w, r, path.Dir(path.Dir(policyPath(r))), "StaticRoutes", validateStaticRoute)
+}
+
+// validateStaticRoute applies a subset of NSX's static route validation, and
+// defaults enabled to true and each next hop's admin_distance to 1.
+func validateStaticRoute(obj object) []client.APIError {
+	invalid := func(message string) client.APIError {
+		return client.APIError{ErrorCode: 503040, ModuleName: "nsx-policy", ErrorMessage: message}
+	}
+
+	var related []client.APIError
+	network, _ := obj["network"].(string)
+	if _, _, err := net.ParseCIDR(network); err != nil {
+		related = append(related, invalid("network "+network+" is not a valid CIDR."))
+	}
+
+	hops, _ := obj["next_hops"].([]any)
+	if len(hops) == 0 {
+		related = append(related, invalid("next_hops must not be empty."))
+	}
+	for _, hop := range hops {
+		hop, _ := hop.(map[string]any)
+		address, _ := hop["ip_address"].(string)
+		if net.ParseIP(address) == nil {
+			related = append(related, invalid("ip_address "+address+" is not a valid IP address."))
+		}
+		distance, ok := hop["admin_distance"].(float64)
+		if !ok {
+			hop["admin_distance"] = 1
+		} else if distance < 1 || distance > 255 {
+			related = append(related, invalid("admin_distance must be between 1 and 255."))
+		}
+	}
+
+	if _, ok := obj["enabled"]; !ok {
+		obj["enabled"] = true
+	}
+	return related
+}
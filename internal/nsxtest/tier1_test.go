@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nsxtest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+func TestTier1InterfaceLifecycle(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddTier1("t1")
+	srv.AddSegment("vlan-100")
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	iface := client.Tier1Interface{
+		Id:           "vlan-100",
+		ResourceType: "Tier1Interface",
+		SegmentPath:  "/infra/segments/vlan-100",
+		Subnets:      []client.InterfaceSubnet{{IpAddresses: []string{"10.0.100.1"}, PrefixLen: 24}},
+	}
+	rsp, err := c.PatchTier1Interface(ctx, client.PatchTier1InterfaceRequest{Tier1Id: "t1", LocaleServiceId: DefaultLocaleServiceId, Interface: iface})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d", rsp.StatusCode)
+	}
+
+	rsp, err = c.GetTier1Interface(ctx, "t1", DefaultLocaleServiceId, "vlan-100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got client.Tier1Interface
+	err = json.NewDecoder(rsp.Body).Decode(&got)
+	rsp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Path != "/infra/tier-1s/t1/locale-services/default/interfaces/vlan-100" || got.UrpfMode != "STRICT" || got.Revision == nil {
+		t.Fatalf("unexpected interface %+v", got)
+	}
+
+	// Updates must carry the current revision.
+	mtu := int64(9000)
+	got.Mtu = &mtu
+	rsp, err = c.UpdateTier1Interface(ctx, client.UpdateTier1InterfaceRequest{Tier1Id: "t1", LocaleServiceId: DefaultLocaleServiceId, Interface: got})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d", rsp.StatusCode)
+	}
+	rsp, err = c.UpdateTier1Interface(ctx, client.UpdateTier1InterfaceRequest{Tier1Id: "t1", LocaleServiceId: DefaultLocaleServiceId, Interface: got})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusPreconditionFailed {
+		t.Fatalf("expected a stale revision to be rejected, got %d", rsp.StatusCode)
+	}
+
+	rsp, err = c.DeleteTier1Interface(ctx, "t1", DefaultLocaleServiceId, "vlan-100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if _, ok := srv.Tier1Interface("t1", DefaultLocaleServiceId, "vlan-100"); ok {
+		t.Fatal("expected the interface to be deleted")
+	}
+}
+
+func TestTier1InterfaceValidation(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddTier1("t1")
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	// The locale service must exist.
+	rsp, err := c.PatchTier1Interface(ctx, client.PatchTier1InterfaceRequest{Tier1Id: "t1", LocaleServiceId: "missing", Interface: client.Tier1Interface{Id: "if"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected a missing locale service to be rejected, got %d", rsp.StatusCode)
+	}
+
+	rsp, err = c.PatchTier1Interface(ctx, client.PatchTier1InterfaceRequest{Tier1Id: "t1", LocaleServiceId: DefaultLocaleServiceId, Interface: client.Tier1Interface{
+		Id:           "if",
+		ResourceType: "Tier1Interface",
+		SegmentPath:  "/infra/segments/missing",
+		Subnets:      []client.InterfaceSubnet{{IpAddresses: []string{"10.0.100.1"}, PrefixLen: 33}},
+		UrpfMode:     "LOOSE",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiErr := client.ParseAPIError(rsp)
+	if rsp.StatusCode != http.StatusBadRequest || len(apiErr.RelatedErrors) != 3 {
+		t.Fatalf("expected 3 related errors, got %d: %v", rsp.StatusCode, apiErr)
+	}
+}
+
+func TestStaticRoute(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddTier1("t1")
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	route := client.StaticRoute{
+		Id:           "behind-fw",
+		ResourceType: "StaticRoutes",
+		Network:      "192.168.0.0/16",
+		NextHops:     []client.RouterNexthop{{IpAddress: "10.0.100.254"}},
+	}
+	rsp, err := c.PatchStaticRoute(ctx, client.PatchStaticRouteRequest{Tier1Id: "t1", StaticRoute: route})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d", rsp.StatusCode)
+	}
+	got, ok := srv.StaticRoute("t1", "behind-fw")
+	if !ok || got.Enabled == nil || !*got.Enabled || got.NextHops[0].AdminDistance != 1 {
+		t.Fatalf("expected NSX defaults to be set, got %+v", got)
+	}
+
+	// The tier-1 gateway must exist.
+	rsp, err = c.PatchStaticRoute(ctx, client.PatchStaticRouteRequest{Tier1Id: "missing", StaticRoute: route})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected a missing tier-1 gateway to be rejected, got %d", rsp.StatusCode)
+	}
+
+	route.Network = "192.168.0.0"
+	route.NextHops = []client.RouterNexthop{{IpAddress: "fw", AdminDistance: 256}}
+	rsp, err = c.PatchStaticRoute(ctx, client.PatchStaticRouteRequest{Tier1Id: "t1", StaticRoute: route})
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiErr := client.ParseAPIError(rsp)
+	if rsp.StatusCode != http.StatusBadRequest || len(apiErr.RelatedErrors) != 3 {
+		t.Fatalf("expected 3 related errors, got %d: %v", rsp.StatusCode, apiErr)
+	}
+
+	rsp, err = c.DeleteStaticRoute(ctx, "t1", "behind-fw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if _, ok := srv.StaticRoute("t1", "behind-fw"); ok {
+		t.Fatal("expected the static route to be deleted")
+	}
+}
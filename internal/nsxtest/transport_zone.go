@@ -0,0 +1,22 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nsxtest
+
+import (
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+// AddTransportZone adds a transport zone to the default enforcement point,
+// as NSX does when a transport zone is created in the fabric.
+func (s *Server) AddTransportZone(tz client.TransportZone) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tz.ResourceType = "PolicyTransportZone"
+	var obj object
+	if err := convert(tz, &obj); err != nil {
+		panic(err)
+	}
+	s.store(client.PolicyContext{}.TransportZonesPath("", "")+"/"+tz.Id, obj, "admin")
+}
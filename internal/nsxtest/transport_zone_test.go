@@ -0,0 +1,37 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package nsxtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+func TestTransportZones(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.PageSize = 1
+	srv.AddTransportZone(client.TransportZone{Id: "overlay-tz", DisplayName: "overlay", TzType: "OVERLAY_BACKED", IsDefault: true})
+	srv.AddTransportZone(client.TransportZone{Id: "vlan-tz", DisplayName: "vlan", TzType: "VLAN_BACKED"})
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	tzs, err := c.ListAllTransportZones(ctx, "", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tzs) != 2 || tzs[1].TzType != "VLAN_BACKED" || tzs[1].Path != "/infra/sites/default/enforcement-points/default/transport-zones/vlan-tz" {
+		t.Fatalf("expected every transport zone across pages, got %+v", tzs)
+	}
+
+	tzs, err = c.ListAllTransportZones(ctx, "other", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tzs) != 0 {
+		t.Fatalf("expected no transport zones at another site, got %+v", tzs)
+	}
+}
@@ -3,14 +3,192 @@
 
 package provider
 
-import "github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+import (
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
 
 type SegmentPort struct {
-	AddressBindings client.PortAddressBindingEntry `tfsdk:"address_bindings"`
-	AdminState      string                         `tfsdk:"admin_state"`
-	Attachment      client.PortAttachment          `tfsdk:"attachment"`
-	Description     string                         `tfsdk:"description"`
-	DisplayName     string                         `tfsdk:"display_name"`
-	Id              string                         `tfsdk:"id"`
-	ResourceType    string                         `tfsdk:"resource_type"`
+	AddressBindings        []PortAddressBindingEntry `tfsdk:"address_bindings"`
+	AdminState             types.String              `tfsdk:"admin_state"`
+	Attachment             *PortAttachment           `tfsdk:"attachment"`
+	Description            types.String              `tfsdk:"description"`
+	DisplayName            types.String              `tfsdk:"display_name"`
+	ExtraConfigs           []SegmentExtraConfig      `tfsdk:"extra_configs"`
+	Id                     types.String              `tfsdk:"id"`
+	IgnoredAddressBindings []PortAddressBindingEntry `tfsdk:"ignored_address_bindings"`
+	InitState              types.String              `tfsdk:"init_state"`
+	ResourceType           types.String              `tfsdk:"resource_type"`
+	Tags                   []Tag                     `tfsdk:"tags"`
+
+	Path          types.String `tfsdk:"path"`
+	ParentPath    types.String `tfsdk:"parent_path"`
+	UniqueId      types.String `tfsdk:"unique_id"`
+	RealizationId types.String `tfsdk:"realization_id"`
+}
+
+type PortAddressBindingEntry struct {
+	IpAddress  types.String `tfsdk:"ip_address"`
+	MacAddress types.String `tfsdk:"mac_address"`
+	VlanId     types.String `tfsdk:"vlan_id"`
+}
+
+type PortAttachment struct {
+	AppId        types.String   `tfsdk:"app_id"`
+	ContextId    types.String   `tfsdk:"context_id"`
+	ContextType  types.String   `tfsdk:"context_type"`
+	EvpnVlans    []types.String `tfsdk:"evpn_vlans"`
+	HyperbusMode types.String   `tfsdk:"hyperbus_mode"`
+	Id           types.String   `tfsdk:"id"`
+	TrafficTag   types.String   `tfsdk:"traffic_tag"`
+	Type         types.String   `tfsdk:"type"`
+}
+
+type Tag struct {
+	Scope types.String `tfsdk:"scope"`
+	Tag   types.String `tfsdk:"tag"`
+}
+
+type SegmentExtraConfig struct {
+	Key   types.String `tfsdk:"key"`
+	Value types.String `tfsdk:"value"`
+}
+
+// NewSegmentPort converts a segment port returned by NSX to its Terraform
+// model. Empty values are mapped to null so that unset optional attributes
+// don't produce a diff.
+func NewSegmentPort(sp client.SegmentPort) SegmentPort {
+	port := SegmentPort{
+		AddressBindings:        newPortAddressBindings(sp.AddressBindings),
+		AdminState:             stringValueOrNull(sp.AdminState),
+		Description:            stringValueOrNull(sp.Description),
+		DisplayName:            stringValueOrNull(sp.DisplayName),
+		Id:                     stringValueOrNull(sp.Id),
+		IgnoredAddressBindings: newPortAddressBindings(sp.IgnoredAddressBindings),
+		InitState:              stringValueOrNull(sp.InitState),
+		ResourceType:           stringValueOrNull(sp.ResourceType),
+		Tags:                   newTags(sp.Tags),
+	}
+	port.setComputed(&sp)
+	for _, config := range sp.ExtraConfigs {
+		port.ExtraConfigs = append(port.ExtraConfigs, SegmentExtraConfig{
+			Key:   types.StringValue(config.ConfigPair.Key),
+			Value: types.StringValue(config.ConfigPair.Value),
+		})
+	}
+	if sp.Attachment != nil {
+		port.Attachment = &PortAttachment{
+			AppId:        stringValueOrNull(sp.Attachment.AppId),
+			ContextId:    stringValueOrNull(sp.Attachment.ContextId),
+			ContextType:  stringValueOrNull(sp.Attachment.ContextType),
+			HyperbusMode: stringValueOrNull(sp.Attachment.HyperbusMode),
+			Id:           stringValueOrNull(sp.Attachment.Id),
+			TrafficTag:   int64StringValueOrNull(sp.Attachment.TrafficTag),
+			Type:         stringValueOrNull(sp.Attachment.Type),
+		}
+		for _, vlans := range sp.Attachment.EvpnVlans {
+			port.Attachment.EvpnVlans = append(port.Attachment.EvpnVlans, types.StringValue(vlans))
+		}
+	}
+	return port
+}
+
+func newPortAddressBindings(bindings []client.PortAddressBindingEntry) []PortAddressBindingEntry {
+	var entries []PortAddressBindingEntry
+	for _, binding := range bindings {
+		entries = append(entries, PortAddressBindingEntry{
+			IpAddress:  stringValueOrNull(binding.IpAddress),
+			MacAddress: stringValueOrNull(binding.MacAddress),
+			VlanId:     int64StringValueOrNull(binding.VlanId),
+		})
+	}
+	return entries
+}
+
+// setComputed copies the attributes NSX sets on a port from sp, which is
+// nil if the port couldn't be read back.
+func (port *SegmentPort) setComputed(sp *client.SegmentPort) {
+	if sp == nil {
+		sp = &client.SegmentPort{}
+	}
+	port.Path = stringValueOrNull(sp.Path)
+	port.ParentPath = stringValueOrNull(sp.ParentPath)
+	port.UniqueId = stringValueOrNull(sp.UniqueId)
+	port.RealizationId = stringValueOrNull(sp.RealizationId)
+}
+
+// ToClient converts the Terraform model to the segment port sent to NSX.
+// Attributes NSX sets are left empty.
+func (sp SegmentPort) ToClient() client.SegmentPort {
+	port := client.SegmentPort{
+		AddressBindings:        portAddressBindingsToClient(sp.AddressBindings),
+		AdminState:             sp.AdminState.ValueString(),
+		Description:            sp.Description.ValueString(),
+		DisplayName:            sp.DisplayName.ValueString(),
+		Id:                     sp.Id.ValueString(),
+		IgnoredAddressBindings: portAddressBindingsToClient(sp.IgnoredAddressBindings),
+		InitState:              sp.InitState.ValueString(),
+		ResourceType:           sp.ResourceType.ValueString(),
+		Tags:                   tagsToClient(sp.Tags),
+	}
+	for _, config := range sp.ExtraConfigs {
+		port.ExtraConfigs = append(port.ExtraConfigs, client.SegmentExtraConfig{
+			ConfigPair: client.UnboundedKeyValuePair{
+				Key:   config.Key.ValueString(),
+				Value: config.Value.ValueString(),
+			},
+		})
+	}
+	if sp.Attachment != nil {
+		port.Attachment = &client.PortAttachment{
+			AppId:        sp.Attachment.AppId.ValueString(),
+			ContextId:    sp.Attachment.ContextId.ValueString(),
+			ContextType:  sp.Attachment.ContextType.ValueString(),
+			HyperbusMode: sp.Attachment.HyperbusMode.ValueString(),
+			Id:           sp.Attachment.Id.ValueString(),
+			TrafficTag:   int64StringValue(sp.Attachment.TrafficTag),
+			Type:         sp.Attachment.Type.ValueString(),
+		}
+		for _, vlans := range sp.Attachment.EvpnVlans {
+			port.Attachment.EvpnVlans = append(port.Attachment.EvpnVlans, vlans.ValueString())
+		}
+	}
+	return port
+}
+
+func portAddressBindingsToClient(entries []PortAddressBindingEntry) []client.PortAddressBindingEntry {
+	var bindings []client.PortAddressBindingEntry
+	for _, entry := range entries {
+		bindings = append(bindings, client.PortAddressBindingEntry{
+			IpAddress:  entry.IpAddress.ValueString(),
+			MacAddress: entry.MacAddress.ValueString(),
+			VlanId:     int64StringValue(entry.VlanId),
+		})
+	}
+	return bindings
+}
+
+func stringValueOrNull(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
+
+// int64StringValueOrNull returns a numeric NSX field, such as a VLAN ID, as
+// the string the schema has always exposed it as.
+func int64StringValueOrNull(i int64) types.String {
+	if i == 0 {
+		return types.StringNull()
+	}
+	return types.StringValue(strconv.FormatInt(i, 10))
+}
+
+// int64StringValue parses a numeric NSX field. Values are checked by
+// int64StringValidator when the configuration is validated.
+func int64StringValue(s types.String) int64 {
+	i, _ := strconv.ParseInt(s.ValueString(), 10, 64)
+	return i
 }
@@ -0,0 +1,166 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+
+	datasourceschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	providerschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+)
+
+// policyContextModel is the optional context block selecting the NSX
+// project or VPC that objects are managed in.
+type policyContextModel struct {
+	OrgId     types.String `tfsdk:"org_id"`
+	ProjectId types.String `tfsdk:"project_id"`
+	VpcId     types.String `tfsdk:"vpc_id"`
+}
+
+const (
+	policyContextDescription = "The NSX multi-tenancy context. Objects are managed in the default space unless a project is set, and in a VPC of that project if a VPC is also set."
+	orgIdDescription         = "NSX organization. Defaults to default."
+	projectIdDescription     = "NSX project."
+	vpcIdDescription         = "NSX VPC in the project. Segments are VPC subnets in a VPC context."
+)
+
+func providerPolicyContextBlock() providerschema.Block {
+	return providerschema.SingleNestedBlock{
+		Description: policyContextDescription + " Resources and data sources can override it with their own context block.",
+		Attributes: map[string]providerschema.Attribute{
+			"org_id":     providerschema.StringAttribute{Optional: true, Description: orgIdDescription},
+			"project_id": providerschema.StringAttribute{Optional: true, Description: projectIdDescription},
+			"vpc_id":     providerschema.StringAttribute{Optional: true, Description: vpcIdDescription},
+		},
+	}
+}
+
+func resourcePolicyContextBlock() resourceschema.Block {
+	return resourceschema.SingleNestedBlock{
+		Description:         policyContextDescription + " Overrides the provider context. Changing it forces a new resource.",
+		MarkdownDescription: policyContextDescription + " Overrides the provider context. Changing it forces a new resource.",
+		PlanModifiers: []planmodifier.Object{
+			objectplanmodifier.RequiresReplace(),
+		},
+		Attributes: map[string]resourceschema.Attribute{
+			"org_id":     resourceschema.StringAttribute{Optional: true, Description: orgIdDescription, MarkdownDescription: orgIdDescription},
+			"project_id": resourceschema.StringAttribute{Optional: true, Description: projectIdDescription, MarkdownDescription: projectIdDescription},
+			"vpc_id":     resourceschema.StringAttribute{Optional: true, Description: vpcIdDescription, MarkdownDescription: vpcIdDescription},
+		},
+	}
+}
+
+func dataSourcePolicyContextBlock() datasourceschema.Block {
+	return datasourceschema.SingleNestedBlock{
+		Description: policyContextDescription + " Overrides the provider context.",
+		Attributes: map[string]datasourceschema.Attribute{
+			"org_id":     datasourceschema.StringAttribute{Optional: true, Description: orgIdDescription},
+			"project_id": datasourceschema.StringAttribute{Optional: true, Description: projectIdDescription},
+			"vpc_id":     datasourceschema.StringAttribute{Optional: true, Description: vpcIdDescription},
+		},
+	}
+}
+
+// merge returns pc with the fields set in m overriding it.
+func (m *policyContextModel) merge(pc client.PolicyContext) client.PolicyContext {
+	if m == nil {
+		return pc
+	}
+	if !m.OrgId.IsNull() {
+		pc.OrgId = m.OrgId.ValueString()
+	}
+	if !m.ProjectId.IsNull() {
+		pc.ProjectId = m.ProjectId.ValueString()
+	}
+	if !m.VpcId.IsNull() {
+		pc.VpcId = m.VpcId.ValueString()
+	}
+	return pc
+}
+
+// newPolicyContextModel returns the context block which selects pc when the
+// provider is configured with the context def, or nil if no block is
+// needed.
+func newPolicyContextModel(pc client.PolicyContext, def client.PolicyContext) *policyContextModel {
+	if pc == def {
+		return nil
+	}
+	return &policyContextModel{
+		OrgId:     contextFieldValue(pc.OrgId, def.OrgId),
+		ProjectId: contextFieldValue(pc.ProjectId, def.ProjectId),
+		VpcId:     contextFieldValue(pc.VpcId, def.VpcId),
+	}
+}
+
+// contextFieldValue returns the value of a context block field which sets
+// value when the provider context sets def. An empty string clears a field
+// set by the provider, such as to select the default space.
+func contextFieldValue(value string, def string) types.String {
+	if value == "" && def == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
+// importContext sets the context block of an object imported by its policy
+// path, importId, to select pc, the context parsed from the path. Objects
+// below /global-infra can only be imported when the provider's
+// global_manager is set, which in turn only imports such objects.
+func importContext(ctx context.Context, state *tfsdk.State, c *client.Client, pc client.PolicyContext, importId string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if pc.GlobalManager && !c.PolicyContext.GlobalManager {
+		diags.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Global objects, below /global-infra, can only be imported when the provider's global_manager is set. Got: %q", importId),
+		)
+		return diags
+	}
+	if !pc.GlobalManager && c.PolicyContext.GlobalManager {
+		diags.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Only global objects, below /global-infra, can be imported when the provider's global_manager is set. Got: %q", importId),
+		)
+		return diags
+	}
+	diags.Append(state.SetAttribute(ctx, path.Root("context"), newPolicyContextModel(pc, c.PolicyContext))...)
+	return diags
+}
+
+// scopedClient returns a client for the objects configured with the
+// context block m.
+func scopedClient(c *client.Client, m *policyContextModel) (*client.Client, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	pc := m.merge(c.PolicyContext)
+	if pc == c.PolicyContext {
+		return c, diags
+	}
+	if err := pc.Validate(); err != nil {
+		diags.AddAttributeError(path.Root("context"), "Invalid NSX Context", err.Error())
+		return nil, diags
+	}
+	return c.InContext(pc), diags
+}
+
+// infraClient returns a client for objects, such as segments or tier-1
+// gateways, which don't exist in a VPC and so reject a VPC context.
+func infraClient(c *client.Client, m *policyContextModel, objects string) (*client.Client, diag.Diagnostics) {
+	scoped, diags := scopedClient(c, m)
+	if scoped != nil && scoped.PolicyContext.IsVpc() {
+		diags.AddAttributeError(path.Root("context"), "Unsupported NSX Context", objects+" can't be managed in a VPC context.")
+		return nil, diags
+	}
+	return scoped, diags
+}
@@ -0,0 +1,229 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &segmentPortDataSource{}
+	_ datasource.DataSourceWithConfigure = &segmentPortDataSource{}
+)
+
+func NewSegmentPortDataSource() datasource.DataSource {
+	return &segmentPortDataSource{}
+}
+
+// segmentPortDataSource reads a single port of a segment, by its id or by
+// the fields which identify it.
+type segmentPortDataSource struct {
+	client *client.Client
+}
+
+type segmentPortDataSourceModel struct {
+	SegmentId    types.String `tfsdk:"segment_id"`
+	PortId       types.String `tfsdk:"port_id"`
+	DisplayName  types.String `tfsdk:"display_name"`
+	AttachmentId types.String `tfsdk:"attachment_id"`
+	TrafficTag   types.String `tfsdk:"traffic_tag"`
+	SegmentPort  *SegmentPort `tfsdk:"segment_port"`
+
+	Context *policyContextModel `tfsdk:"context"`
+}
+
+func (d *segmentPortDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*NsxtIntervlanRoutingProviderData)
+	if !ok {
+		tflog.Error(ctx, "Unable to prepare client")
+		return
+	}
+	d.client = data.Client
+}
+
+// Metadata returns the data source type name.
+func (d *segmentPortDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_segment_port"
+}
+
+// Schema defines the schema for the data source.
+func (d *segmentPortDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Read a single Segment Port, by its id or by the one port of the segment matching display_name, attachment_id and traffic_tag.",
+		Attributes: map[string]schema.Attribute{
+			"segment_id": schema.StringAttribute{
+				Description: "Identifier for this segment.",
+				Required:    true,
+			},
+			"port_id": schema.StringAttribute{
+				Description: "Identifier of the port. Can't be combined with the other filters.",
+				Optional:    true,
+			},
+			"display_name": schema.StringAttribute{
+				Description: "Only match the port with this display name.",
+				Optional:    true,
+			},
+			"attachment_id": schema.StringAttribute{
+				Description: "Only match the port whose attachment has this id, the VIF attachment ID.",
+				Optional:    true,
+			},
+			"traffic_tag": schema.StringAttribute{
+				Description: "Only match the port whose attachment tags traffic with this VLAN ID.",
+				Optional:    true,
+				Validators:  []validator.String{vlanIdValidator()},
+			},
+			"segment_port": schema.SingleNestedAttribute{
+				Description: "The segment port.",
+				Computed:    true,
+				Attributes:  segmentPortDataSourceAttributes(),
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"context": dataSourcePolicyContextBlock(),
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *segmentPortDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Debug(ctx, "Preparing to read segment port data source")
+	var state segmentPortDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var filters []string
+	if !state.DisplayName.IsNull() {
+		filters = append(filters, fmt.Sprintf("display_name %q", state.DisplayName.ValueString()))
+	}
+	if !state.AttachmentId.IsNull() {
+		filters = append(filters, fmt.Sprintf("attachment_id %q", state.AttachmentId.ValueString()))
+	}
+	if !state.TrafficTag.IsNull() {
+		filters = append(filters, fmt.Sprintf("traffic_tag %q", state.TrafficTag.ValueString()))
+	}
+	if state.PortId.IsNull() == (len(filters) == 0) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("port_id"),
+			"Invalid Segment Port Lookup",
+			"Either port_id, or one or more of display_name, attachment_id and traffic_tag must be set.",
+		)
+		return
+	}
+
+	c, diags := scopedClient(d.client, state.Context)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	segment_id := state.SegmentId.ValueString()
+	var port *client.SegmentPort
+	if !state.PortId.IsNull() {
+		port, _, diags = readSegmentPort(ctx, c, segment_id, state.PortId.ValueString())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if port == nil {
+			resp.Diagnostics.AddError(
+				"Segment Port Not Found",
+				fmt.Sprintf("Segment %s has no port %s.", segment_id, state.PortId.ValueString()),
+			)
+			return
+		}
+	} else {
+		port, diags = findSegmentPort(ctx, c, state, filters)
+		resp.Diagnostics.Append(diags...)
+		if port == nil {
+			return
+		}
+	}
+
+	segmentPort := NewSegmentPort(*port)
+	state.SegmentPort = &segmentPort
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Debug(ctx, "Finished reading segment port data source", map[string]any{"success": true})
+}
+
+// findSegmentPort returns the one port of the segment of m which matches
+// its filters, described by filters. It returns nil with an error if no
+// port or more than one port matches.
+func findSegmentPort(ctx context.Context, c *client.Client, m segmentPortDataSourceModel, filters []string) (*client.SegmentPort, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	segment_id := m.SegmentId.ValueString()
+	segmentPorts, err := c.ListAllSegmentPorts(ctx, segment_id, nil)
+	if err != nil {
+		addAPIError(&diags, "Unable to Read segment ports for "+segment_id, err, nil)
+		return nil, diags
+	}
+
+	var matches []client.SegmentPort
+	for _, port := range segmentPorts {
+		if m.matches(port) {
+			matches = append(matches, port)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		diags.AddError(
+			"Segment Port Not Found",
+			fmt.Sprintf("No port of segment %s matches %s.", segment_id, strings.Join(filters, " and ")),
+		)
+		return nil, diags
+	case 1:
+		return &matches[0], diags
+	}
+
+	ids := make([]string, 0, len(matches))
+	for _, port := range matches {
+		ids = append(ids, port.Id)
+	}
+	diags.AddError(
+		"Multiple Segment Ports Found",
+		fmt.Sprintf("%d ports of segment %s match %s: %s. Add filters, or use port_id, to select one.", len(matches), segment_id, strings.Join(filters, " and "), strings.Join(ids, ", ")),
+	)
+	return nil, diags
+}
+
+// matches reports whether port matches every filter which is set.
+func (m segmentPortDataSourceModel) matches(port client.SegmentPort) bool {
+	if !m.DisplayName.IsNull() && port.DisplayName != m.DisplayName.ValueString() {
+		return false
+	}
+	if m.AttachmentId.IsNull() && m.TrafficTag.IsNull() {
+		return true
+	}
+	if port.Attachment == nil {
+		return false
+	}
+	if !m.AttachmentId.IsNull() && port.Attachment.Id != m.AttachmentId.ValueString() {
+		return false
+	}
+	if !m.TrafficTag.IsNull() && strconv.FormatInt(port.Attachment.TrafficTag, 10) != m.TrafficTag.ValueString() {
+		return false
+	}
+	return true
+}
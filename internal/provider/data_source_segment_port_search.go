@@ -0,0 +1,135 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &segmentPortSearchDataSource{}
+	_ datasource.DataSourceWithConfigure = &segmentPortSearchDataSource{}
+)
+
+func NewSegmentPortSearchDataSource() datasource.DataSource {
+	return &segmentPortSearchDataSource{}
+}
+
+// segmentPortSearchDataSource finds segment ports across every segment with
+// the NSX search API.
+type segmentPortSearchDataSource struct {
+	client *client.Client
+}
+
+type segmentPortSearchDataSourceModel struct {
+	Query   types.String              `tfsdk:"query"`
+	Results []segmentPortSearchResult `tfsdk:"results"`
+
+	Context *policyContextModel `tfsdk:"context"`
+}
+
+type segmentPortSearchResult struct {
+	SegmentId   types.String `tfsdk:"segment_id"`
+	SegmentPort SegmentPort  `tfsdk:"segment_port"`
+}
+
+func (d *segmentPortSearchDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*NsxtIntervlanRoutingProviderData)
+	if !ok {
+		tflog.Error(ctx, "Unable to prepare client")
+		return
+	}
+	d.client = data.Client
+}
+
+// Metadata returns the data source type name.
+func (d *segmentPortSearchDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_segment_port_search"
+}
+
+// Schema defines the schema for the data source.
+func (d *segmentPortSearchDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Search the Segment Ports of every segment with the NSX search API.",
+		Attributes: map[string]schema.Attribute{
+			"query": schema.StringAttribute{
+				Description: "Lucene query matched against the fields of segment ports, such as attachment.traffic_tag:1001 or display_name:web*. Every port is returned if it is empty.",
+				Required:    true,
+			},
+			"results": schema.ListNestedAttribute{
+				Description: "Segment ports matching the query.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"segment_id": schema.StringAttribute{
+							Description: "Identifier of the segment the port belongs to.",
+							Computed:    true,
+						},
+						"segment_port": schema.SingleNestedAttribute{
+							Description: "The segment port.",
+							Computed:    true,
+							Attributes:  segmentPortDataSourceAttributes(),
+						},
+					},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"context": dataSourcePolicyContextBlock(),
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *segmentPortSearchDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Debug(ctx, "Preparing to read segment port search data source")
+	var state segmentPortSearchDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := scopedClient(d.client, state.Context)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ports, err := c.SearchSegmentPorts(ctx, state.Query.ValueString(), nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Unable to Search segment ports", err, nil)
+		return
+	}
+
+	// A project search also returns the ports of the project's VPCs, so
+	// results are narrowed down to the context.
+	root := c.PolicyContext.InfraPath() + "/"
+	state.Results = make([]segmentPortSearchResult, 0, len(ports))
+	for _, port := range ports {
+		if !strings.HasPrefix(port.ParentPath, root) {
+			continue
+		}
+		state.Results = append(state.Results, segmentPortSearchResult{
+			SegmentId:   types.StringValue(path.Base(port.ParentPath)),
+			SegmentPort: NewSegmentPort(port),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Debug(ctx, "Finished reading segment port search data source", map[string]any{"results": len(state.Results)})
+}
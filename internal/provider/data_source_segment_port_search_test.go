@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+func TestAccSegmentPortSearchDataSource(t *testing.T) {
+	srv := testAccNewServer(t)
+	srv.PageSize = 1
+	for segment, tag := range map[string]int64{"parent-segment": 1001, "child-segment": 1002} {
+		srv.SetSegmentPort(segment, client.SegmentPort{
+			Id:           "web",
+			DisplayName:  "web",
+			AdminState:   "UP",
+			ResourceType: "SegmentPort",
+			Attachment:   &client.PortAttachment{Id: "web", Type: "CHILD", TrafficTag: tag},
+		})
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccChildPortConfig(srv, "1001") + `
+data "nsxt-intervlan-routing_segment_port_search" "test" {
+  query = "attachment.traffic_tag:1001"
+
+  depends_on = [nsxt-intervlan-routing_segment_port.child]
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					// Ports are found across segments, and every page is read.
+					statecheck.ExpectKnownValue("data.nsxt-intervlan-routing_segment_port_search.test", tfjsonpath.New("results"), knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.ObjectExact(map[string]knownvalue.Check{
+							"segment_id": knownvalue.StringExact("child-segment"),
+							"segment_port": knownvalue.ObjectPartial(map[string]knownvalue.Check{
+								"id":   knownvalue.StringExact("child-port"),
+								"path": knownvalue.StringExact("/infra/segments/child-segment/ports/child-port"),
+							}),
+						}),
+						knownvalue.ObjectExact(map[string]knownvalue.Check{
+							"segment_id": knownvalue.StringExact("parent-segment"),
+							"segment_port": knownvalue.ObjectPartial(map[string]knownvalue.Check{
+								"id": knownvalue.StringExact("web"),
+								"attachment": knownvalue.ObjectPartial(map[string]knownvalue.Check{
+									"traffic_tag": knownvalue.StringExact("1001"),
+								}),
+							}),
+						}),
+					})),
+				},
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segment_port_search" "test" {
+  query = "display_name OR id"
+}
+`,
+				ExpectError: regexp.MustCompile(`Unable to Search segment ports`),
+			},
+		},
+	})
+}
@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+func TestAccSegmentPortDataSource(t *testing.T) {
+	srv := testAccNewServer(t)
+	srv.PageSize = 1
+	for id, tag := range map[string]int64{"port-a": 1001, "port-b": 1002, "port-c": 1002} {
+		srv.SetSegmentPort("child-segment", client.SegmentPort{
+			Id:           id,
+			DisplayName:  "child",
+			AdminState:   "UP",
+			ResourceType: "SegmentPort",
+			Attachment:   &client.PortAttachment{Id: id + "-vif", Type: "CHILD", TrafficTag: tag},
+		})
+	}
+
+	const port = "data.nsxt-intervlan-routing_segment_port.test"
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segment_port" "test" {
+  segment_id = "child-segment"
+  port_id    = "port-b"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(port, tfjsonpath.New("segment_port").AtMapKey("attachment").AtMapKey("id"), knownvalue.StringExact("port-b-vif")),
+				},
+			},
+			// Ports are found on any page.
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segment_port" "test" {
+  segment_id  = "child-segment"
+  traffic_tag = "1001"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(port, tfjsonpath.New("segment_port").AtMapKey("id"), knownvalue.StringExact("port-a")),
+				},
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segment_port" "test" {
+  segment_id    = "child-segment"
+  display_name  = "child"
+  attachment_id = "port-c-vif"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(port, tfjsonpath.New("segment_port").AtMapKey("id"), knownvalue.StringExact("port-c")),
+				},
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segment_port" "test" {
+  segment_id  = "child-segment"
+  traffic_tag = "1002"
+}
+`,
+				ExpectError: regexp.MustCompile(`2 ports of segment child-segment match traffic_tag "1002": port-b, port-c`),
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segment_port" "test" {
+  segment_id   = "child-segment"
+  display_name = "parent"
+}
+`,
+				ExpectError: regexp.MustCompile(`No port of segment child-segment matches display_name "parent"`),
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segment_port" "test" {
+  segment_id = "child-segment"
+  port_id    = "missing"
+}
+`,
+				ExpectError: regexp.MustCompile(`Segment child-segment has no port missing`),
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segment_port" "test" {
+  segment_id   = "child-segment"
+  port_id      = "port-a"
+  display_name = "child"
+}
+`,
+				ExpectError: regexp.MustCompile(`Either port_id, or one or more of display_name`),
+			},
+		},
+	})
+}
@@ -5,12 +5,13 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 
 	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
@@ -28,21 +29,27 @@ type segmentPortsDataSource struct {
 }
 
 type segmentPortsDataSourceModel struct {
-	SegmentId    string        `tfsdk:"segment_id"`
+	SegmentId    types.String  `tfsdk:"segment_id"`
+	Tags         []tagFilter   `tfsdk:"tags"`
 	SegmentPorts []SegmentPort `tfsdk:"segment_ports"`
+
+	Site             types.String `tfsdk:"site"`
+	EnforcementPoint types.String `tfsdk:"enforcement_point"`
+
+	Context *policyContextModel `tfsdk:"context"`
 }
 
-func (d segmentPortsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+func (d *segmentPortsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.Client)
+	data, ok := req.ProviderData.(*NsxtIntervlanRoutingProviderData)
 	if !ok {
 		tflog.Error(ctx, "Unable to prepare client")
 		return
 	}
-	d.client = client
+	d.client = data.Client
 }
 
 // Metadata returns the data source type name.
@@ -59,6 +66,183 @@ func (d *segmentPortsDataSource) Schema(_ context.Context, _ datasource.SchemaRe
 				Description: "Identifier for this segment.",
 				Required:    true,
 			},
+			"site": schema.StringAttribute{
+				Description: "Federation site whose Local Manager the ports are read from. Requires the provider's global_manager.",
+				Optional:    true,
+			},
+			"enforcement_point": schema.StringAttribute{
+				Description: "Enforcement point of site to read the ports from. Defaults to default.",
+				Optional:    true,
+			},
+			"tags": tagFilterAttribute("ports"),
+			"segment_ports": schema.ListNestedAttribute{
+				Description: "Every port of the segment, or those matching tags.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: segmentPortDataSourceAttributes(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"context": dataSourcePolicyContextBlock(),
+		},
+	}
+}
+
+// segmentPortDataSourceAttributes describes a segment port returned by a
+// data source.
+func segmentPortDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"address_bindings": schema.ListNestedAttribute{
+			Description: "List of IP address bindings.",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"ip_address": schema.StringAttribute{
+						Description: "IP address of segment port",
+						Computed:    true,
+					},
+					"mac_address": schema.StringAttribute{
+						Description: "MAC address of segment port",
+						Computed:    true,
+					},
+					"vlan_id": schema.StringAttribute{
+						Description: "VLAN ID associated with this segment port",
+						Computed:    true,
+					},
+				},
+			},
+		},
+		"admin_state": schema.StringAttribute{
+			Description: "Admin state of the segment port.",
+			Computed:    true,
+		},
+		"attachment": schema.SingleNestedAttribute{
+			Description: "Attachment object definition",
+			Computed:    true,
+			Attributes: map[string]schema.Attribute{
+				"id": schema.StringAttribute{
+					Description: "VIF UUID in NSX.",
+					Computed:    true,
+				},
+				"context_id": schema.StringAttribute{
+					Description: "Attachment UUID of the PARENT port.",
+					Computed:    true,
+				},
+				"traffic_tag": schema.StringAttribute{
+					Description: "VLAN ID to tag traffic with.",
+					Computed:    true,
+				},
+				"context_type": schema.StringAttribute{
+					Description: "Type of the parent of a CHILD attachment.",
+					Computed:    true,
+				},
+				"evpn_vlans": schema.ListAttribute{
+					Description: "VLAN ranges of an EVPN tenant.",
+					Computed:    true,
+					ElementType: types.StringType,
+				},
+				"hyperbus_mode": schema.StringAttribute{
+					Description: "Whether the attachment uses hyperbus for container traffic.",
+					Computed:    true,
+				},
+				"app_id": schema.StringAttribute{
+					Description: "Application ID associated with this port.",
+					Computed:    true,
+				},
+				"type": schema.StringAttribute{
+					Description: "Type of attachment. Either PARENT or CHILD.",
+					Computed:    true,
+				},
+			},
+		},
+		"description": schema.StringAttribute{
+			Description: "Description of segment port",
+			Computed:    true,
+		},
+		"display_name": schema.StringAttribute{
+			Description: "Display name of segment port",
+			Computed:    true,
+		},
+		"id": schema.StringAttribute{
+			Description: "Id of segment port.",
+			Computed:    true,
+		},
+		"resource_type": schema.StringAttribute{
+			Description: "Resource type of segment port.",
+			Computed:    true,
+		},
+		"ignored_address_bindings": schema.ListNestedAttribute{
+			Description: "IP address bindings which NSX doesn't bind to the port.",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"ip_address": schema.StringAttribute{
+						Description: "IP address to ignore",
+						Computed:    true,
+					},
+					"mac_address": schema.StringAttribute{
+						Description: "MAC address to ignore",
+						Computed:    true,
+					},
+					"vlan_id": schema.StringAttribute{
+						Description: "VLAN ID of the ignored binding",
+						Computed:    true,
+					},
+				},
+			},
+		},
+		"init_state": schema.StringAttribute{
+			Description: "Initial state of the port when it was created.",
+			Computed:    true,
+		},
+		"extra_configs": schema.ListNestedAttribute{
+			Description: "Vendor specific configuration passed through to the port's hypervisor.",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"key": schema.StringAttribute{
+						Description: "Configuration key",
+						Computed:    true,
+					},
+					"value": schema.StringAttribute{
+						Description: "Configuration value",
+						Computed:    true,
+					},
+				},
+			},
+		},
+		"tags": schema.SetNestedAttribute{
+			Description: "NSX tags of the segment port.",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"scope": schema.StringAttribute{
+						Description: "Scope of the tag",
+						Computed:    true,
+					},
+					"tag": schema.StringAttribute{
+						Description: "Value of the tag",
+						Computed:    true,
+					},
+				},
+			},
+		},
+		"path": schema.StringAttribute{
+			Description: "Policy path of the segment port.",
+			Computed:    true,
+		},
+		"parent_path": schema.StringAttribute{
+			Description: "Policy path of the segment the port belongs to.",
+			Computed:    true,
+		},
+		"unique_id": schema.StringAttribute{
+			Description: "NSX unique identifier of the segment port.",
+			Computed:    true,
+		},
+		"realization_id": schema.StringAttribute{
+			Description: "Identifier of the logical port NSX realized the segment port as.",
+			Computed:    true,
 		},
 	}
 }
@@ -69,47 +253,51 @@ func (d *segmentPortsDataSource) Read(ctx context.Context, req datasource.ReadRe
 	var state segmentPortsDataSourceModel
 
 	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	portsResponse, err := d.client.ListSegmentPorts(ctx, state.SegmentId)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Read segment ports for ",
-			err.Error(),
-		)
+	c, diags := scopedClient(d.client, state.Context)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	var segmentPorts client.ListSegmentPortsResponse
-	if portsResponse.StatusCode != 200 {
-		resp.Diagnostics.AddError(
-			"Unexpected HTTP error code received for Item",
-			portsResponse.Status,
+	var reqEditors []client.RequestEditorFn
+	if site := state.Site.ValueString(); site != "" {
+		if !c.PolicyContext.GlobalManager {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("site"),
+				"Invalid Federation Site",
+				"Segment ports can only be read from a site when the provider's global_manager is set.",
+			)
+			return
+		}
+		reqEditors = append(reqEditors, client.WithEnforcementPoint(client.EnforcementPointPath(site, state.EnforcementPoint.ValueString())))
+	} else if !state.EnforcementPoint.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("enforcement_point"),
+			"Missing Federation Site",
+			"enforcement_point can only be set along with site.",
 		)
 		return
 	}
 
-	if err := json.NewDecoder(portsResponse.Body).Decode(&segmentPorts); err != nil {
-		resp.Diagnostics.AddError(
-			"Invalid format received for segment ports",
-			err.Error(),
-		)
+	// Read every page so that segments with more ports than the NSX page
+	// size are returned in full.
+	segmentPorts, err := c.ListAllSegmentPorts(ctx, state.SegmentId.ValueString(), nil, reqEditors...)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Unable to Read segment ports for "+state.SegmentId.ValueString(), err, nil)
 		return
 	}
 
 	// Map response body to model
-	state = segmentPortsDataSourceModel{}
-	state.SegmentId = state.SegmentId
-	for _, segment := range segmentPorts.Results {
-		state.SegmentPorts = append(
-			state.SegmentPorts,
-			SegmentPort{
-				AddressBindings: segment.AddressBindings,
-				AdminState:      segment.AdminState,
-				Attachment:      segment.Attachment,
-				Description:     segment.Description,
-				DisplayName:     segment.DisplayName,
-				Id:              segment.Id,
-			})
+	state.SegmentPorts = make([]SegmentPort, 0, len(segmentPorts))
+	for _, segmentPort := range segmentPorts {
+		if !matchesTagFilters(segmentPort.Tags, state.Tags) {
+			continue
+		}
+		state.SegmentPorts = append(state.SegmentPorts, NewSegmentPort(segmentPort))
 	}
 
 	// Set state
@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+func TestAccSegmentPortsDataSource(t *testing.T) {
+	srv := testAccNewServer(t)
+	srv.PageSize = 2
+	for id, env := range map[string]string{"port-a": "prod", "port-b": "dev", "port-c": ""} {
+		port := client.SegmentPort{
+			Id:           id,
+			DisplayName:  id,
+			AdminState:   "UP",
+			ResourceType: "SegmentPort",
+			Attachment:   &client.PortAttachment{Id: id, Type: "PARENT"},
+		}
+		if env != "" {
+			port.Tags = []client.Tag{{Scope: "env", Tag: env}}
+		}
+		srv.SetSegmentPort("child-segment", port)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccChildPortConfig(srv, "1001") + `
+data "nsxt-intervlan-routing_segment_ports" "test" {
+  segment_id = "child-segment"
+
+  depends_on = [nsxt-intervlan-routing_segment_port.child]
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("data.nsxt-intervlan-routing_segment_ports.test", tfjsonpath.New("segment_id"), knownvalue.StringExact("child-segment")),
+					// Every page is returned, including the port managed by Terraform.
+					statecheck.ExpectKnownValue("data.nsxt-intervlan-routing_segment_ports.test", tfjsonpath.New("segment_ports"), knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.ObjectPartial(map[string]knownvalue.Check{
+							"id":          knownvalue.StringExact("child-port"),
+							"admin_state": knownvalue.StringExact("UP"),
+							"attachment": knownvalue.ObjectPartial(map[string]knownvalue.Check{
+								"type":        knownvalue.StringExact("CHILD"),
+								"traffic_tag": knownvalue.StringExact("1001"),
+							}),
+						}),
+						knownvalue.ObjectPartial(map[string]knownvalue.Check{"id": knownvalue.StringExact("port-a")}),
+						knownvalue.ObjectPartial(map[string]knownvalue.Check{"id": knownvalue.StringExact("port-b")}),
+						knownvalue.ObjectPartial(map[string]knownvalue.Check{"id": knownvalue.StringExact("port-c")}),
+					})),
+				},
+			},
+			// Ports are filtered by tag scope, and by scope and value.
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segment_ports" "env" {
+  segment_id = "child-segment"
+  tags = [
+    {
+      scope = "env"
+    },
+  ]
+}
+
+data "nsxt-intervlan-routing_segment_ports" "prod" {
+  segment_id = "child-segment"
+  tags = [
+    {
+      scope = "env"
+      tag   = "prod"
+    },
+  ]
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("data.nsxt-intervlan-routing_segment_ports.env", tfjsonpath.New("segment_ports"), knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.ObjectPartial(map[string]knownvalue.Check{"id": knownvalue.StringExact("port-a")}),
+						knownvalue.ObjectPartial(map[string]knownvalue.Check{"id": knownvalue.StringExact("port-b")}),
+					})),
+					statecheck.ExpectKnownValue("data.nsxt-intervlan-routing_segment_ports.prod", tfjsonpath.New("segment_ports"), knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.ObjectPartial(map[string]knownvalue.Check{
+							"id": knownvalue.StringExact("port-a"),
+							"tags": knownvalue.SetExact([]knownvalue.Check{
+								knownvalue.ObjectExact(map[string]knownvalue.Check{"scope": knownvalue.StringExact("env"), "tag": knownvalue.StringExact("prod")}),
+							}),
+						}),
+					})),
+				},
+			},
+		},
+	})
+}
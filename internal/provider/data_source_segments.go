@@ -0,0 +1,347 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &segmentsDataSource{}
+	_ datasource.DataSourceWithConfigure = &segmentsDataSource{}
+)
+
+func NewSegmentsDataSource() datasource.DataSource {
+	return &segmentsDataSource{}
+}
+
+// segmentsDataSource lists the segments matching its filters, so that
+// segment IDs can be looked up rather than copied from the NSX UI.
+type segmentsDataSource struct {
+	client *client.Client
+}
+
+type segmentsDataSourceModel struct {
+	DisplayName       types.String   `tfsdk:"display_name"`
+	DisplayNameRegex  types.String   `tfsdk:"display_name_regex"`
+	VlanIds           []types.String `tfsdk:"vlan_ids"`
+	TransportZonePath types.String   `tfsdk:"transport_zone_path"`
+	ConnectivityPath  types.String   `tfsdk:"connectivity_path"`
+	Tags              []tagFilter    `tfsdk:"tags"`
+	Segments          []segmentModel `tfsdk:"segments"`
+
+	Context *policyContextModel `tfsdk:"context"`
+}
+
+type segmentModel struct {
+	Id                types.String         `tfsdk:"id"`
+	DisplayName       types.String         `tfsdk:"display_name"`
+	Description       types.String         `tfsdk:"description"`
+	TransportZonePath types.String         `tfsdk:"transport_zone_path"`
+	VlanIds           []types.String       `tfsdk:"vlan_ids"`
+	ConnectivityPath  types.String         `tfsdk:"connectivity_path"`
+	Subnets           []segmentSubnetModel `tfsdk:"subnets"`
+	AdminState        types.String         `tfsdk:"admin_state"`
+	Tags              []Tag                `tfsdk:"tags"`
+	Path              types.String         `tfsdk:"path"`
+}
+
+func (d *segmentsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*NsxtIntervlanRoutingProviderData)
+	if !ok {
+		tflog.Error(ctx, "Unable to prepare client")
+		return
+	}
+	d.client = data.Client
+}
+
+// Metadata returns the data source type name.
+func (d *segmentsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_segments"
+}
+
+// Schema defines the schema for the data source.
+func (d *segmentsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List the Segments matching every filter which is set, or every segment if none is.",
+		Attributes: map[string]schema.Attribute{
+			"display_name": schema.StringAttribute{
+				Description: "Only return segments with this display name.",
+				Optional:    true,
+			},
+			"display_name_regex": schema.StringAttribute{
+				Description:         "Only return segments whose display name matches this regular expression, in RE2 syntax. Anchor it with ^ and $ to match the whole name.",
+				MarkdownDescription: "Only return segments whose display name matches this regular expression, in [RE2 syntax](https://github.com/google/re2/wiki/Syntax). Anchor it with `^` and `$` to match the whole name.",
+				Optional:            true,
+				Validators:          []validator.String{regexpValidator{}},
+			},
+			"vlan_ids": schema.SetAttribute{
+				Description: "Only return segments backed by each of these VLAN IDs or ranges, such as 1001 or 1001-1010. A segment backed by a range, such as 1001-1010, is returned for each VLAN ID in it.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators:  []validator.Set{vlanRangesValidator{}},
+			},
+			"transport_zone_path": schema.StringAttribute{
+				Description: "Only return segments in the transport zone with this policy path.",
+				Optional:    true,
+			},
+			"connectivity_path": schema.StringAttribute{
+				Description: "Only return segments connected to the gateway with this policy path, such as /infra/tier-1s/<id>.",
+				Optional:    true,
+			},
+			"tags": tagFilterAttribute("segments"),
+			"segments": schema.ListNestedAttribute{
+				Description: "The matching segments.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: segmentDataSourceAttributes(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"context": dataSourcePolicyContextBlock(),
+		},
+	}
+}
+
+// segmentDataSourceAttributes describes a segment returned by a data source.
+func segmentDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Description: "Identifier of the segment.",
+			Computed:    true,
+		},
+		"display_name": schema.StringAttribute{
+			Description: "Display name of the segment.",
+			Computed:    true,
+		},
+		"description": schema.StringAttribute{
+			Description: "Description of the segment.",
+			Computed:    true,
+		},
+		"transport_zone_path": schema.StringAttribute{
+			Description: "Policy path of the transport zone of the segment.",
+			Computed:    true,
+		},
+		"vlan_ids": schema.ListAttribute{
+			Description: "VLAN IDs or ranges of a VLAN backed segment.",
+			Computed:    true,
+			ElementType: types.StringType,
+		},
+		"connectivity_path": schema.StringAttribute{
+			Description: "Policy path of the gateway the segment is connected to.",
+			Computed:    true,
+		},
+		"subnets": schema.ListNestedAttribute{
+			Description: "Subnets of the segment.",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"gateway_address": schema.StringAttribute{
+						Description: "Gateway address of the subnet in CIDR notation.",
+						Computed:    true,
+					},
+					"dhcp_ranges": schema.ListAttribute{
+						Description: "DHCP address ranges of the subnet.",
+						Computed:    true,
+						ElementType: types.StringType,
+					},
+					"network": schema.StringAttribute{
+						Description: "Network address of the subnet.",
+						Computed:    true,
+					},
+				},
+			},
+		},
+		"admin_state": schema.StringAttribute{
+			Description: "Admin state of the segment.",
+			Computed:    true,
+		},
+		"tags": schema.SetNestedAttribute{
+			Description: "NSX tags of the segment.",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"scope": schema.StringAttribute{
+						Description: "Scope of the tag",
+						Computed:    true,
+					},
+					"tag": schema.StringAttribute{
+						Description: "Value of the tag",
+						Computed:    true,
+					},
+				},
+			},
+		},
+		"path": schema.StringAttribute{
+			Description: "Policy path of the segment.",
+			Computed:    true,
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *segmentsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Debug(ctx, "Preparing to read segments data source")
+	var state segmentsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := infraClient(d.client, state.Context, "Segments")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// Read every page, as NSX can't filter segments itself.
+	segments, err := c.ListAllSegments(ctx, nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Unable to Read segments", err, nil)
+		return
+	}
+
+	state.Segments = make([]segmentModel, 0, len(segments))
+	for _, segment := range segments {
+		if state.matches(segment) {
+			state.Segments = append(state.Segments, newSegmentModel(segment))
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Debug(ctx, "Finished reading segments data source", map[string]any{"segments": len(state.Segments)})
+}
+
+// matches reports whether segment matches every filter which is set.
+func (m segmentsDataSourceModel) matches(segment client.Segment) bool {
+	if !matchesDisplayName(segment.DisplayName, m.DisplayName, m.DisplayNameRegex) {
+		return false
+	}
+	if !m.TransportZonePath.IsNull() && segment.TransportZonePath != m.TransportZonePath.ValueString() {
+		return false
+	}
+	if !m.ConnectivityPath.IsNull() && segment.ConnectivityPath != m.ConnectivityPath.ValueString() {
+		return false
+	}
+	for _, vlan := range m.VlanIds {
+		if !backsVlans(segment.VlanIds, vlan.ValueString()) {
+			return false
+		}
+	}
+	return matchesTagFilters(segment.Tags, m.Tags)
+}
+
+// backsVlans reports whether the VLAN IDs and ranges of a segment include
+// every VLAN ID of vlans, a VLAN ID or range. A segment backed by
+// 1001-1010 backs 1005, for example, but not 1005-1020, and one backed by
+// 1001-1010 and 1011-1020 backs 1005-1015.
+func backsVlans(vlanIds []string, vlans string) bool {
+	first, last, ok := parseVlanRange(vlans)
+	if !ok {
+		return false
+	}
+	return slices.ContainsFunc(mergeVlanRanges(vlanIds), func(r [2]int) bool {
+		return r[0] <= first && last <= r[1]
+	})
+}
+
+// mergeVlanRanges returns the VLAN IDs and ranges of vlanIds as sorted
+// ranges, with overlapping and adjacent ones merged. Invalid values are
+// skipped.
+func mergeVlanRanges(vlanIds []string) [][2]int {
+	ranges := make([][2]int, 0, len(vlanIds))
+	for _, r := range vlanIds {
+		if first, last, ok := parseVlanRange(r); ok {
+			ranges = append(ranges, [2]int{first, last})
+		}
+	}
+	slices.SortFunc(ranges, func(a, b [2]int) int { return a[0] - b[0] })
+
+	merged := ranges[:0]
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r[0] <= merged[n-1][1]+1 {
+			merged[n-1][1] = max(merged[n-1][1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// parseVlanRange parses a VLAN ID, such as 100, or an inclusive range of
+// them, such as 200-300, as in the vlan_ids of a segment. VLAN IDs are
+// between 0 and 4094.
+func parseVlanRange(s string) (int, int, bool) {
+	lo, hi, isRange := strings.Cut(s, "-")
+	first, err := strconv.Atoi(strings.TrimSpace(lo))
+	if err != nil || first < 0 || first > 4094 {
+		return 0, 0, false
+	}
+	if !isRange {
+		return first, first, true
+	}
+	last, err := strconv.Atoi(strings.TrimSpace(hi))
+	if err != nil || last < first || last > 4094 {
+		return 0, 0, false
+	}
+	return first, last, true
+}
+
+// matchesDisplayName reports whether displayName equals exact and matches
+// the regular expression pattern, either of which may be null.
+func matchesDisplayName(displayName string, exact types.String, pattern types.String) bool {
+	if !exact.IsNull() && displayName != exact.ValueString() {
+		return false
+	}
+	if pattern.IsNull() {
+		return true
+	}
+	// The pattern was checked by regexpValidator.
+	matched, err := regexp.MatchString(pattern.ValueString(), displayName)
+	return err == nil && matched
+}
+
+func newSegmentModel(segment client.Segment) segmentModel {
+	m := segmentModel{
+		Id:                types.StringValue(segment.Id),
+		DisplayName:       stringValueOrNull(segment.DisplayName),
+		Description:       stringValueOrNull(segment.Description),
+		TransportZonePath: stringValueOrNull(segment.TransportZonePath),
+		ConnectivityPath:  stringValueOrNull(segment.ConnectivityPath),
+		AdminState:        stringValueOrNull(segment.AdminState),
+		Tags:              newTags(segment.Tags),
+		Path:              stringValueOrNull(segment.Path),
+	}
+	for _, vlan := range segment.VlanIds {
+		m.VlanIds = append(m.VlanIds, types.StringValue(vlan))
+	}
+	for _, subnet := range segment.Subnets {
+		s := segmentSubnetModel{
+			GatewayAddress: types.StringValue(subnet.GatewayAddress),
+			Network:        stringValueOrNull(subnet.Network),
+		}
+		for _, r := range subnet.DhcpRanges {
+			s.DhcpRanges = append(s.DhcpRanges, types.StringValue(r))
+		}
+		m.Subnets = append(m.Subnets, s)
+	}
+	return m
+}
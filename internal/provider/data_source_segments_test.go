@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+func TestAccSegmentsDataSource(t *testing.T) {
+	const vlanTz = "/infra/sites/default/enforcement-points/default/transport-zones/vlan-tz"
+	srv := testAccNewServer(t)
+	srv.PageSize = 1
+	srv.SetSegment(client.Segment{Id: "vlan-1001", DisplayName: "fw-vlan-1001", ResourceType: "Segment", TransportZonePath: vlanTz, VlanIds: []string{"1001"}, Tags: []client.Tag{{Scope: "tier", Tag: "web"}}})
+	srv.SetSegment(client.Segment{Id: "vlan-1002", DisplayName: "fw-vlan-1002", ResourceType: "Segment", TransportZonePath: vlanTz, VlanIds: []string{"1002"}, Tags: []client.Tag{{Scope: "tier", Tag: "db"}}})
+	srv.SetSegment(client.Segment{Id: "web", DisplayName: "web", ResourceType: "Segment", ConnectivityPath: "/infra/tier-1s/t1"})
+	srv.SetSegment(client.Segment{Id: "trunk", DisplayName: "trunk", ResourceType: "Segment", TransportZonePath: vlanTz, VlanIds: []string{"2000-2010", "2020"}})
+	srv.SetSegment(client.Segment{Id: "split", DisplayName: "split", ResourceType: "Segment", TransportZonePath: vlanTz, VlanIds: []string{"3005-3010", "3000-3004"}})
+
+	const segments = "data.nsxt-intervlan-routing_segments.test"
+	ids := func(ids ...string) knownvalue.Check {
+		var checks []knownvalue.Check
+		for _, id := range ids {
+			checks = append(checks, knownvalue.ObjectPartial(map[string]knownvalue.Check{"id": knownvalue.StringExact(id)}))
+		}
+		return knownvalue.ListExact(checks)
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Segments are found on every page.
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segments" "test" {
+  display_name_regex = "^fw-vlan-"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(segments, tfjsonpath.New("segments"), ids("vlan-1001", "vlan-1002")),
+					statecheck.ExpectKnownValue(segments, tfjsonpath.New("segments").AtSliceIndex(0).AtMapKey("transport_zone_path"), knownvalue.StringExact(vlanTz)),
+				},
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segments" "test" {
+  transport_zone_path = "` + vlanTz + `"
+  vlan_ids            = ["1002"]
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(segments, tfjsonpath.New("segments"), ids("vlan-1002")),
+				},
+			},
+			// A VLAN ID matches the segments backing a range including it.
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segments" "test" {
+  vlan_ids = ["2005", "2008-2010"]
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(segments, tfjsonpath.New("segments"), ids("trunk")),
+				},
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segments" "test" {
+  vlan_ids = ["2005-2020"]
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(segments, tfjsonpath.New("segments"), knownvalue.ListSizeExact(0)),
+				},
+			},
+			// Adjacent ranges back the VLAN IDs they cover together.
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segments" "test" {
+  vlan_ids = ["3002-3008"]
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(segments, tfjsonpath.New("segments"), ids("split")),
+				},
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segments" "test" {
+  vlan_ids = ["4095"]
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid VLAN ID`),
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segments" "test" {
+  vlan_ids = ["vlan-1001"]
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid VLAN ID`),
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segments" "test" {
+  tags = [
+    {
+      tag = "web"
+    },
+  ]
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(segments, tfjsonpath.New("segments"), ids("vlan-1001")),
+				},
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segments" "test" {
+  display_name      = "web"
+  connectivity_path = "/infra/tier-1s/t1"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(segments, tfjsonpath.New("segments"), ids("web")),
+				},
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segments" "test" {
+  display_name = "missing"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(segments, tfjsonpath.New("segments"), knownvalue.ListSizeExact(0)),
+				},
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_segments" "test" {
+  display_name_regex = "fw-(vlan"
+}
+`,
+				ExpectError: regexp.MustCompile(`value must be a valid regular expression`),
+			},
+		},
+	})
+}
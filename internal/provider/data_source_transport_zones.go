@@ -0,0 +1,198 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"context"
+
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &transportZonesDataSource{}
+	_ datasource.DataSourceWithConfigure = &transportZonesDataSource{}
+)
+
+func NewTransportZonesDataSource() datasource.DataSource {
+	return &transportZonesDataSource{}
+}
+
+// transportZonesDataSource lists the transport zones matching its filters,
+// for the transport_zone_path of segments.
+type transportZonesDataSource struct {
+	client *client.Client
+}
+
+type transportZonesDataSourceModel struct {
+	Site             types.String         `tfsdk:"site"`
+	EnforcementPoint types.String         `tfsdk:"enforcement_point"`
+	DisplayName      types.String         `tfsdk:"display_name"`
+	DisplayNameRegex types.String         `tfsdk:"display_name_regex"`
+	TzType           types.String         `tfsdk:"tz_type"`
+	Tags             []tagFilter          `tfsdk:"tags"`
+	TransportZones   []transportZoneModel `tfsdk:"transport_zones"`
+
+	Context *policyContextModel `tfsdk:"context"`
+}
+
+type transportZoneModel struct {
+	Id          types.String `tfsdk:"id"`
+	DisplayName types.String `tfsdk:"display_name"`
+	Description types.String `tfsdk:"description"`
+	TzType      types.String `tfsdk:"tz_type"`
+	IsDefault   types.Bool   `tfsdk:"is_default"`
+	Tags        []Tag        `tfsdk:"tags"`
+	Path        types.String `tfsdk:"path"`
+}
+
+func (d *transportZonesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*NsxtIntervlanRoutingProviderData)
+	if !ok {
+		tflog.Error(ctx, "Unable to prepare client")
+		return
+	}
+	d.client = data.Client
+}
+
+// Metadata returns the data source type name.
+func (d *transportZonesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_transport_zones"
+}
+
+// Schema defines the schema for the data source.
+func (d *transportZonesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List the Transport Zones of an enforcement point matching every filter which is set, or every transport zone if none is.",
+		Attributes: map[string]schema.Attribute{
+			"site": schema.StringAttribute{
+				Description: "Site whose transport zones are listed. Defaults to default, the site of an NSX Manager outside a Federation.",
+				Optional:    true,
+			},
+			"enforcement_point": schema.StringAttribute{
+				Description: "Enforcement point of site whose transport zones are listed. Defaults to default.",
+				Optional:    true,
+			},
+			"display_name": schema.StringAttribute{
+				Description: "Only return transport zones with this display name.",
+				Optional:    true,
+			},
+			"display_name_regex": schema.StringAttribute{
+				Description:         "Only return transport zones whose display name matches this regular expression, in RE2 syntax. Anchor it with ^ and $ to match the whole name.",
+				MarkdownDescription: "Only return transport zones whose display name matches this regular expression, in [RE2 syntax](https://github.com/google/re2/wiki/Syntax). Anchor it with `^` and `$` to match the whole name.",
+				Optional:            true,
+				Validators:          []validator.String{regexpValidator{}},
+			},
+			"tz_type": schema.StringAttribute{
+				Description: "Only return transport zones of this type, such as VLAN_BACKED or OVERLAY_BACKED.",
+				Optional:    true,
+			},
+			"tags": tagFilterAttribute("transport zones"),
+			"transport_zones": schema.ListNestedAttribute{
+				Description: "The matching transport zones.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "Identifier of the transport zone.",
+							Computed:    true,
+						},
+						"display_name": schema.StringAttribute{
+							Description: "Display name of the transport zone.",
+							Computed:    true,
+						},
+						"description": schema.StringAttribute{
+							Description: "Description of the transport zone.",
+							Computed:    true,
+						},
+						"tz_type": schema.StringAttribute{
+							Description: "Type of the transport zone, such as VLAN_BACKED or OVERLAY_BACKED.",
+							Computed:    true,
+						},
+						"is_default": schema.BoolAttribute{
+							Description: "Whether the transport zone is the default of its type.",
+							Computed:    true,
+						},
+						"tags": schema.SetNestedAttribute{
+							Description: "NSX tags of the transport zone.",
+							Computed:    true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"scope": schema.StringAttribute{
+										Description: "Scope of the tag",
+										Computed:    true,
+									},
+									"tag": schema.StringAttribute{
+										Description: "Value of the tag",
+										Computed:    true,
+									},
+								},
+							},
+						},
+						"path": schema.StringAttribute{
+							Description: "Policy path of the transport zone, the transport_zone_path of its segments.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"context": dataSourcePolicyContextBlock(),
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *transportZonesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Debug(ctx, "Preparing to read transport zones data source")
+	var state transportZonesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := infraClient(d.client, state.Context, "Transport zones")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tzs, err := c.ListAllTransportZones(ctx, state.Site.ValueString(), state.EnforcementPoint.ValueString(), nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Unable to Read transport zones", err, nil)
+		return
+	}
+
+	state.TransportZones = make([]transportZoneModel, 0, len(tzs))
+	for _, tz := range tzs {
+		if !matchesDisplayName(tz.DisplayName, state.DisplayName, state.DisplayNameRegex) ||
+			(!state.TzType.IsNull() && tz.TzType != state.TzType.ValueString()) ||
+			!matchesTagFilters(tz.Tags, state.Tags) {
+			continue
+		}
+		state.TransportZones = append(state.TransportZones, transportZoneModel{
+			Id:          types.StringValue(tz.Id),
+			DisplayName: stringValueOrNull(tz.DisplayName),
+			Description: stringValueOrNull(tz.Description),
+			TzType:      stringValueOrNull(tz.TzType),
+			IsDefault:   types.BoolValue(tz.IsDefault),
+			Tags:        newTags(tz.Tags),
+			Path:        stringValueOrNull(tz.Path),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Debug(ctx, "Finished reading transport zones data source", map[string]any{"transport_zones": len(state.TransportZones)})
+}
@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+func TestAccTransportZonesDataSource(t *testing.T) {
+	srv := testAccNewServer(t)
+	srv.AddTransportZone(client.TransportZone{Id: "overlay-tz", DisplayName: "nsx-overlay-transportzone", TzType: "OVERLAY_BACKED", IsDefault: true})
+	srv.AddTransportZone(client.TransportZone{Id: "vlan-tz", DisplayName: "nsx-vlan-transportzone", TzType: "VLAN_BACKED", IsDefault: true})
+	srv.AddTransportZone(client.TransportZone{Id: "edge-tz", DisplayName: "edge-vlan-transportzone", TzType: "VLAN_BACKED", Tags: []client.Tag{{Scope: "role", Tag: "edge"}}})
+
+	const tzs = "data.nsxt-intervlan-routing_transport_zones.test"
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The transport zone path is the transport_zone_path of a
+			// segment.
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_transport_zones" "test" {
+  display_name = "nsx-vlan-transportzone"
+}
+
+data "nsxt-intervlan-routing_segments" "vlan" {
+  transport_zone_path = data.nsxt-intervlan-routing_transport_zones.test.transport_zones[0].path
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(tzs, tfjsonpath.New("transport_zones"), knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.ObjectExact(map[string]knownvalue.Check{
+							"id":           knownvalue.StringExact("vlan-tz"),
+							"display_name": knownvalue.StringExact("nsx-vlan-transportzone"),
+							"description":  knownvalue.Null(),
+							"tz_type":      knownvalue.StringExact("VLAN_BACKED"),
+							"is_default":   knownvalue.Bool(true),
+							"tags":         knownvalue.Null(),
+							"path":         knownvalue.StringExact("/infra/sites/default/enforcement-points/default/transport-zones/vlan-tz"),
+						}),
+					})),
+					statecheck.ExpectKnownValue("data.nsxt-intervlan-routing_segments.vlan", tfjsonpath.New("segments"), knownvalue.ListSizeExact(0)),
+				},
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_transport_zones" "test" {
+  tz_type = "VLAN_BACKED"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(tzs, tfjsonpath.New("transport_zones"), knownvalue.ListSizeExact(2)),
+				},
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_transport_zones" "test" {
+  display_name_regex = "vlan"
+  tags = [
+    {
+      scope = "role"
+      tag   = "edge"
+    },
+  ]
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(tzs, tfjsonpath.New("transport_zones").AtSliceIndex(0).AtMapKey("id"), knownvalue.StringExact("edge-tz")),
+					statecheck.ExpectKnownValue(tzs, tfjsonpath.New("transport_zones"), knownvalue.ListSizeExact(1)),
+				},
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_transport_zones" "test" {
+  site = "paris"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(tzs, tfjsonpath.New("transport_zones"), knownvalue.ListSizeExact(0)),
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"context"
+
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &vifsDataSource{}
+	_ datasource.DataSourceWithConfigure = &vifsDataSource{}
+)
+
+func NewVifsDataSource() datasource.DataSource {
+	return &vifsDataSource{}
+}
+
+// vifsDataSource lists the VIFs in the NSX fabric inventory, such as the one
+// a port is attached to or those of a VM known by its external ID.
+type vifsDataSource struct {
+	client *client.Client
+}
+
+type vifsDataSourceModel struct {
+	OwnerVmId         types.String     `tfsdk:"owner_vm_id"`
+	LportAttachmentId types.String     `tfsdk:"lport_attachment_id"`
+	Vifs              []fabricVifModel `tfsdk:"vifs"`
+}
+
+type fabricVifModel struct {
+	ExternalId        types.String `tfsdk:"external_id"`
+	DisplayName       types.String `tfsdk:"display_name"`
+	OwnerVmId         types.String `tfsdk:"owner_vm_id"`
+	HostId            types.String `tfsdk:"host_id"`
+	LportAttachmentId types.String `tfsdk:"lport_attachment_id"`
+	MacAddress        types.String `tfsdk:"mac_address"`
+	DeviceKey         types.String `tfsdk:"device_key"`
+	DeviceName        types.String `tfsdk:"device_name"`
+}
+
+func (d *vifsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*NsxtIntervlanRoutingProviderData)
+	if !ok {
+		tflog.Error(ctx, "Unable to prepare client")
+		return
+	}
+	d.client = data.Client
+}
+
+// Metadata returns the data source type name.
+func (d *vifsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vifs"
+}
+
+// Schema defines the schema for the data source.
+func (d *vifsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List the VIFs in the NSX fabric inventory by the VM they belong to or by their attachment ID, such as to find the VM and MAC address behind a segment port's attachment.",
+		Attributes: map[string]schema.Attribute{
+			"owner_vm_id": schema.StringAttribute{
+				Description: "Only return the VIFs of the VM with this external ID, its BIOS UUID.",
+				Optional:    true,
+			},
+			"lport_attachment_id": schema.StringAttribute{
+				Description: "Only return the VIF with this attachment ID, the attachment id of a PARENT port or the context_id of a CHILD port.",
+				Optional:    true,
+			},
+			"vifs": schema.ListNestedAttribute{
+				Description: "The matching VIFs.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"external_id": schema.StringAttribute{
+							Description: "External ID of the VIF.",
+							Computed:    true,
+						},
+						"display_name": schema.StringAttribute{
+							Description: "Display name of the VIF.",
+							Computed:    true,
+						},
+						"owner_vm_id": schema.StringAttribute{
+							Description: "External ID of the VM the VIF belongs to, the external_id of the virtual_machine data source.",
+							Computed:    true,
+						},
+						"host_id": schema.StringAttribute{
+							Description: "Identifier of the transport node the VIF's VM runs on.",
+							Computed:    true,
+						},
+						"lport_attachment_id": schema.StringAttribute{
+							Description: "Attachment ID of the VIF, the attachment id of a PARENT port and the context_id of its CHILD ports.",
+							Computed:    true,
+						},
+						"mac_address": schema.StringAttribute{
+							Description: "MAC address of the VIF.",
+							Computed:    true,
+						},
+						"device_key": schema.StringAttribute{
+							Description: "Device key of the VIF in the VM, such as 4000.",
+							Computed:    true,
+						},
+						"device_name": schema.StringAttribute{
+							Description: "Device name of the VIF in the VM, such as Network adapter 1.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *vifsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Debug(ctx, "Preparing to read VIFs data source")
+	var state vifsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	vifs, err := d.client.ListAllVifs(ctx, &client.ListVifsParams{
+		OwnerVmId:         state.OwnerVmId.ValueString(),
+		LportAttachmentId: state.LportAttachmentId.ValueString(),
+	})
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Unable to Read VIFs", err, nil)
+		return
+	}
+
+	state.Vifs = make([]fabricVifModel, 0, len(vifs))
+	for _, vif := range vifs {
+		state.Vifs = append(state.Vifs, fabricVifModel{
+			ExternalId:        types.StringValue(vif.ExternalId),
+			DisplayName:       stringValueOrNull(vif.DisplayName),
+			OwnerVmId:         stringValueOrNull(vif.OwnerVmId),
+			HostId:            stringValueOrNull(vif.HostId),
+			LportAttachmentId: stringValueOrNull(vif.LportAttachmentId),
+			MacAddress:        stringValueOrNull(vif.MacAddress),
+			DeviceKey:         stringValueOrNull(vif.DeviceKey),
+			DeviceName:        stringValueOrNull(vif.DeviceName),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Debug(ctx, "Finished reading VIFs data source", map[string]any{"vifs": len(state.Vifs)})
+}
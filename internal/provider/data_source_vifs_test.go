@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+func TestAccVifsDataSource(t *testing.T) {
+	srv := testAccNewServer(t)
+	srv.AddVirtualMachine(client.VirtualMachine{DisplayName: "fw", ExternalId: "vm-1"},
+		client.VirtualNetworkInterface{
+			ExternalId:        "vm-1-4000",
+			DeviceKey:         "4000",
+			DeviceName:        "Network adapter 1",
+			HostId:            "host-1",
+			LportAttachmentId: "9765bf41-9725-4714-977e-7f7395920de2",
+			MacAddress:        "00:50:56:ad:5e:64",
+		},
+		client.VirtualNetworkInterface{
+			ExternalId:        "vm-1-4001",
+			DeviceKey:         "4001",
+			LportAttachmentId: "attachment-2",
+		},
+	)
+	srv.AddVirtualMachine(client.VirtualMachine{DisplayName: "web", ExternalId: "vm-2"},
+		client.VirtualNetworkInterface{ExternalId: "vm-2-4000", LportAttachmentId: "attachment-3"},
+	)
+
+	const vifs = "data.nsxt-intervlan-routing_vifs.test"
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// A port's attachment ID finds the VIF, and so the VM, behind
+			// it.
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_vifs" "test" {
+  lport_attachment_id = "9765bf41-9725-4714-977e-7f7395920de2"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(vifs, tfjsonpath.New("vifs"), knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.ObjectExact(map[string]knownvalue.Check{
+							"external_id":         knownvalue.StringExact("vm-1-4000"),
+							"display_name":        knownvalue.Null(),
+							"owner_vm_id":         knownvalue.StringExact("vm-1"),
+							"host_id":             knownvalue.StringExact("host-1"),
+							"lport_attachment_id": knownvalue.StringExact("9765bf41-9725-4714-977e-7f7395920de2"),
+							"mac_address":         knownvalue.StringExact("00:50:56:ad:5e:64"),
+							"device_key":          knownvalue.StringExact("4000"),
+							"device_name":         knownvalue.StringExact("Network adapter 1"),
+						}),
+					})),
+				},
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_vifs" "test" {
+  owner_vm_id = "vm-1"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(vifs, tfjsonpath.New("vifs"), knownvalue.ListSizeExact(2)),
+					statecheck.ExpectKnownValue(vifs, tfjsonpath.New("vifs").AtSliceIndex(1).AtMapKey("lport_attachment_id"), knownvalue.StringExact("attachment-2")),
+				},
+			},
+			// Both filters must match.
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_vifs" "test" {
+  owner_vm_id         = "vm-1"
+  lport_attachment_id = "attachment-3"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(vifs, tfjsonpath.New("vifs"), knownvalue.ListSizeExact(0)),
+				},
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_vifs" "test" {}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(vifs, tfjsonpath.New("vifs"), knownvalue.ListSizeExact(3)),
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,185 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &virtualMachineDataSource{}
+	_ datasource.DataSourceWithConfigure = &virtualMachineDataSource{}
+)
+
+func NewVirtualMachineDataSource() datasource.DataSource {
+	return &virtualMachineDataSource{}
+}
+
+// virtualMachineDataSource looks up a VM and its VIFs in the NSX fabric
+// inventory, so that ports can be attached to its VIFs by reference.
+type virtualMachineDataSource struct {
+	client *client.Client
+}
+
+type virtualMachineDataSourceModel struct {
+	DisplayName types.String `tfsdk:"display_name"`
+	ExternalId  types.String `tfsdk:"external_id"`
+	HostId      types.String `tfsdk:"host_id"`
+	PowerState  types.String `tfsdk:"power_state"`
+	Vifs        []vifModel   `tfsdk:"vifs"`
+}
+
+type vifModel struct {
+	LportAttachmentId types.String `tfsdk:"lport_attachment_id"`
+	MacAddress        types.String `tfsdk:"mac_address"`
+	DeviceKey         types.String `tfsdk:"device_key"`
+	DeviceName        types.String `tfsdk:"device_name"`
+	ExternalId        types.String `tfsdk:"external_id"`
+}
+
+func (d *virtualMachineDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*NsxtIntervlanRoutingProviderData)
+	if !ok {
+		tflog.Error(ctx, "Unable to prepare client")
+		return
+	}
+	d.client = data.Client
+}
+
+// Metadata returns the data source type name.
+func (d *virtualMachineDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_virtual_machine"
+}
+
+// Schema defines the schema for the data source.
+func (d *virtualMachineDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Look up a VM and its VIFs in the NSX fabric inventory by display name or external ID, to attach segment ports to its VIFs.",
+		Attributes: map[string]schema.Attribute{
+			"display_name": schema.StringAttribute{
+				Description: "Display name of the VM. Exactly one of display_name or external_id must be set, and must match exactly one VM.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"external_id": schema.StringAttribute{
+				Description: "External ID of the VM, its BIOS UUID. Exactly one of display_name or external_id must be set.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"host_id": schema.StringAttribute{
+				Description: "Identifier of the transport node the VM runs on.",
+				Computed:    true,
+			},
+			"power_state": schema.StringAttribute{
+				Description: "Power state of the VM, such as VM_RUNNING.",
+				Computed:    true,
+			},
+			"vifs": schema.ListNestedAttribute{
+				Description: "VIFs of the VM.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"lport_attachment_id": schema.StringAttribute{
+							Description: "Attachment ID of the VIF, the attachment id of a PARENT port and the context_id of its CHILD ports.",
+							Computed:    true,
+						},
+						"mac_address": schema.StringAttribute{
+							Description: "MAC address of the VIF.",
+							Computed:    true,
+						},
+						"device_key": schema.StringAttribute{
+							Description: "Device key of the VIF in the VM, such as 4000.",
+							Computed:    true,
+						},
+						"device_name": schema.StringAttribute{
+							Description: "Device name of the VIF in the VM, such as Network adapter 1.",
+							Computed:    true,
+						},
+						"external_id": schema.StringAttribute{
+							Description: "External ID of the VIF.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *virtualMachineDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Debug(ctx, "Preparing to read virtual machine data source")
+	var state virtualMachineDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params := &client.ListVirtualMachinesParams{
+		DisplayName: state.DisplayName.ValueString(),
+		ExternalId:  state.ExternalId.ValueString(),
+	}
+	if (params.DisplayName == "") == (params.ExternalId == "") {
+		resp.Diagnostics.AddAttributeError(path.Root("display_name"), "Invalid Virtual Machine Lookup", "Exactly one of display_name or external_id must be set.")
+		return
+	}
+	lookup := fmt.Sprintf("display_name %q", params.DisplayName)
+	if params.ExternalId != "" {
+		lookup = fmt.Sprintf("external_id %q", params.ExternalId)
+	}
+
+	vms, err := d.client.ListAllVirtualMachines(ctx, params)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Unable to Read virtual machines", err, nil)
+		return
+	}
+	switch len(vms) {
+	case 0:
+		resp.Diagnostics.AddError("Virtual Machine Not Found", fmt.Sprintf("No VM with %s was found in the NSX inventory.", lookup))
+		return
+	case 1:
+	default:
+		resp.Diagnostics.AddError("Multiple Virtual Machines Found", fmt.Sprintf("%d VMs with %s were found in the NSX inventory. Look the VM up by its external_id instead.", len(vms), lookup))
+		return
+	}
+	vm := vms[0]
+
+	vifs, err := d.client.ListAllVifs(ctx, &client.ListVifsParams{OwnerVmId: vm.ExternalId})
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Unable to Read VIFs", err, nil)
+		return
+	}
+
+	state.DisplayName = types.StringValue(vm.DisplayName)
+	state.ExternalId = types.StringValue(vm.ExternalId)
+	state.HostId = stringValueOrNull(vm.HostId)
+	state.PowerState = stringValueOrNull(vm.PowerState)
+	state.Vifs = make([]vifModel, 0, len(vifs))
+	for _, vif := range vifs {
+		state.Vifs = append(state.Vifs, vifModel{
+			LportAttachmentId: stringValueOrNull(vif.LportAttachmentId),
+			MacAddress:        stringValueOrNull(vif.MacAddress),
+			DeviceKey:         stringValueOrNull(vif.DeviceKey),
+			DeviceName:        stringValueOrNull(vif.DeviceName),
+			ExternalId:        types.StringValue(vif.ExternalId),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Debug(ctx, "Finished reading virtual machine data source", map[string]any{"vifs": len(state.Vifs)})
+}
@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+func TestAccVirtualMachineDataSource(t *testing.T) {
+	srv := testAccNewServer(t)
+	srv.AddVirtualMachine(client.VirtualMachine{DisplayName: "fw", ExternalId: "5012e2a8-0e4f-4d31-a3c5-2f1b0b4e8d11", PowerState: "VM_RUNNING"},
+		client.VirtualNetworkInterface{
+			ExternalId:        "5012e2a8-0e4f-4d31-a3c5-2f1b0b4e8d11-4000",
+			DeviceKey:         "4000",
+			DeviceName:        "Network adapter 1",
+			LportAttachmentId: "9765bf41-9725-4714-977e-7f7395920de2",
+			MacAddress:        "00:50:56:ad:5e:64",
+		},
+	)
+	srv.AddVirtualMachine(client.VirtualMachine{DisplayName: "clone", ExternalId: "vm-2"})
+	srv.AddVirtualMachine(client.VirtualMachine{DisplayName: "clone", ExternalId: "vm-3"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The VIF's attachment ID can be used as the attachment of a
+			// PARENT port.
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_virtual_machine" "fw" {
+  display_name = "fw"
+}
+
+resource "nsxt-intervlan-routing_segment_port" "parent" {
+  segment_id = "parent-segment"
+  port_id    = "parent-port"
+  segment_port = {
+    attachment = {
+      id   = data.nsxt-intervlan-routing_virtual_machine.fw.vifs[0].lport_attachment_id
+      type = "PARENT"
+    }
+    display_name  = "fw"
+    id            = "parent-port"
+    resource_type = "SegmentPort"
+  }
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("data.nsxt-intervlan-routing_virtual_machine.fw", tfjsonpath.New("external_id"), knownvalue.StringExact("5012e2a8-0e4f-4d31-a3c5-2f1b0b4e8d11")),
+					statecheck.ExpectKnownValue("data.nsxt-intervlan-routing_virtual_machine.fw", tfjsonpath.New("vifs"), knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.ObjectExact(map[string]knownvalue.Check{
+							"lport_attachment_id": knownvalue.StringExact("9765bf41-9725-4714-977e-7f7395920de2"),
+							"mac_address":         knownvalue.StringExact("00:50:56:ad:5e:64"),
+							"device_key":          knownvalue.StringExact("4000"),
+							"device_name":         knownvalue.StringExact("Network adapter 1"),
+							"external_id":         knownvalue.StringExact("5012e2a8-0e4f-4d31-a3c5-2f1b0b4e8d11-4000"),
+						}),
+					})),
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("segment_port").AtMapKey("attachment").AtMapKey("id"), knownvalue.StringExact("9765bf41-9725-4714-977e-7f7395920de2")),
+				},
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_virtual_machine" "clone" {
+  external_id = "vm-3"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("data.nsxt-intervlan-routing_virtual_machine.clone", tfjsonpath.New("display_name"), knownvalue.StringExact("clone")),
+					statecheck.ExpectKnownValue("data.nsxt-intervlan-routing_virtual_machine.clone", tfjsonpath.New("vifs"), knownvalue.ListSizeExact(0)),
+				},
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_virtual_machine" "clone" {
+  display_name = "clone"
+}
+`,
+				ExpectError: regexp.MustCompile(`2 VMs with display_name "clone" were found`),
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "nsxt-intervlan-routing_virtual_machine" "missing" {
+  display_name = "missing"
+}
+`,
+				ExpectError: regexp.MustCompile(`No VM with display_name "missing" was found`),
+			},
+		},
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+// fieldPath maps an NSX field name which may appear in an error message to
+// the attribute it is configured by. Lists of field paths are matched in
+// order, so nested fields come before the fields which contain them.
+type fieldPath struct {
+	field *regexp.Regexp
+	path  path.Path
+}
+
+func newFieldPath(field string, p path.Path) fieldPath {
+	return fieldPath{
+		field: regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(field) + `\b`),
+		path:  p,
+	}
+}
+
+// segmentPortFieldPaths list the attachment's fields before attachment, so
+// that an error about attachment.context_id is reported against context_id
+// rather than the whole attachment.
+var segmentPortFieldPaths = []fieldPath{
+	newFieldPath("context_id", path.Root("segment_port").AtName("attachment").AtName("context_id")),
+	newFieldPath("traffic_tag", path.Root("segment_port").AtName("attachment").AtName("traffic_tag")),
+	newFieldPath("app_id", path.Root("segment_port").AtName("attachment").AtName("app_id")),
+	newFieldPath("attachment", path.Root("segment_port").AtName("attachment")),
+	newFieldPath("address_bindings", path.Root("segment_port").AtName("address_bindings")),
+	newFieldPath("admin_state", path.Root("segment_port").AtName("admin_state")),
+	newFieldPath("display_name", path.Root("segment_port").AtName("display_name")),
+	newFieldPath("description", path.Root("segment_port").AtName("description")),
+	newFieldPath("resource_type", path.Root("segment_port").AtName("resource_type")),
+}
+
+// segmentFieldPaths report errors about the fields of a subnet, such as
+// gateway_address, against subnets.
+var segmentFieldPaths = []fieldPath{
+	newFieldPath("gateway_address", path.Root("subnets")),
+	newFieldPath("dhcp_ranges", path.Root("subnets")),
+	newFieldPath("subnets", path.Root("subnets")),
+	newFieldPath("transport_zone_path", path.Root("transport_zone_path")),
+	newFieldPath("vlan_ids", path.Root("vlan_ids")),
+	newFieldPath("connectivity_path", path.Root("connectivity_path")),
+	newFieldPath("admin_state", path.Root("admin_state")),
+	newFieldPath("display_name", path.Root("display_name")),
+	newFieldPath("description", path.Root("description")),
+}
+
+var tier1InterfaceFieldPaths = []fieldPath{
+	newFieldPath("segment_path", path.Root("segment_path")),
+	newFieldPath("ip_addresses", path.Root("subnets")),
+	newFieldPath("prefix_len", path.Root("subnets")),
+	newFieldPath("subnets", path.Root("subnets")),
+	newFieldPath("mtu", path.Root("mtu")),
+	newFieldPath("urpf_mode", path.Root("urpf_mode")),
+	newFieldPath("display_name", path.Root("display_name")),
+	newFieldPath("description", path.Root("description")),
+}
+
+var staticRouteFieldPaths = []fieldPath{
+	newFieldPath("network", path.Root("network")),
+	newFieldPath("ip_address", path.Root("next_hops")),
+	newFieldPath("admin_distance", path.Root("next_hops")),
+	newFieldPath("scope", path.Root("next_hops")),
+	newFieldPath("next_hops", path.Root("next_hops")),
+	newFieldPath("display_name", path.Root("display_name")),
+	newFieldPath("description", path.Root("description")),
+}
+
+// addRevisionConflict reports an update NSX rejected because the object
+// kind id had been modified since Terraform last read it at revision.
+// current is the revision the object was read at again after the conflict,
+// nil if it couldn't be read, and lastModifiedUser who modified it. Callers
+// refresh state from that read, so that the next plan shows what changed.
+func addRevisionConflict(diags *diag.Diagnostics, kind string, id string, revision int64, current *int64, lastModifiedUser string) {
+	detail := fmt.Sprintf("%s %s has been modified outside of Terraform since it was last read at revision %d", kind, id, revision)
+	if current != nil {
+		detail += fmt.Sprintf(", and is now at revision %d", *current)
+	}
+	if lastModifiedUser != "" {
+		detail += fmt.Sprintf(" (last modified by %s)", lastModifiedUser)
+	}
+	diags.AddError(
+		kind+" Revision Conflict",
+		detail+". Run terraform plan to review the changes before applying again, "+
+			"or set last_writer_wins in the provider configuration to overwrite them.",
+	)
+}
+
+// addAPIError reports an error returned by the NSX client. NSX API errors
+// are expanded so that each related error becomes its own diagnostic, and
+// are attached to an attribute when the message names one of fields.
+func addAPIError(diags *diag.Diagnostics, summary string, err error, fields []fieldPath) {
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		diags.AddError(summary, err.Error())
+		return
+	}
+	addAPIErrorDiagnostics(diags, summary, apiErr, fields)
+}
+
+func addAPIErrorDiagnostics(diags *diag.Diagnostics, summary string, apiErr *client.APIError, fields []fieldPath) {
+	detail := apiErr.Error()
+	if apiErr.Details != "" {
+		detail += "\n\n" + apiErr.Details
+	}
+
+	matched := false
+	for _, f := range fields {
+		if f.field.MatchString(apiErr.ErrorMessage) {
+			diags.AddAttributeError(f.path, summary, detail)
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		diags.AddError(summary, detail)
+	}
+
+	for i := range apiErr.RelatedErrors {
+		addAPIErrorDiagnostics(diags, summary, &apiErr.RelatedErrors[i], fields)
+	}
+}
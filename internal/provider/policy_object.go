@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+// readObject fetches a policy object of kind from NSX with get, decoding it
+// as T. A nil object without errors means that the object does not exist.
+// The object is also returned as read, for an update to be merged onto with
+// client.MergeObject.
+//
+// The resources for policy objects share a lifecycle. Create PATCHes the
+// object and, as PATCH doesn't return it, reads it back for the attributes
+// NSX sets. Update reads the object first, for its system tags and the
+// fields the resource doesn't model, then PUTs it back merged onto the
+// object as read, with the revision from updateRevision.
+func readObject[T any](get func() (*http.Response, error), kind string, fields []fieldPath) (*T, map[string]any, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	rsp, err := get()
+	if err != nil {
+		diags.AddError("Unable to Read "+kind, err.Error())
+		return nil, nil, diags
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode == http.StatusNotFound {
+		return nil, nil, diags
+	}
+	if rsp.StatusCode != http.StatusOK {
+		addAPIError(&diags, "Unable to Read "+kind, client.ParseAPIError(rsp), fields)
+		return nil, nil, diags
+	}
+
+	var obj T
+	var current map[string]any
+	buf, err := io.ReadAll(rsp.Body)
+	if err == nil {
+		err = json.Unmarshal(buf, &obj)
+	}
+	if err == nil {
+		err = json.Unmarshal(buf, &current)
+	}
+	if err != nil {
+		diags.AddError("Invalid format received for "+kind, err.Error())
+		return nil, nil, diags
+	}
+	return &obj, current, diags
+}
+
+// updateRevision returns the revision to PUT an object with: the revision in
+// state, so that NSX rejects the update if the object has been modified
+// since it was last read, or with last_writer_wins the revision just read,
+// so that the update overwrites those changes. A PATCH wouldn't do, as it
+// leaves the fields removed from the configuration unchanged.
+func updateRevision(state types.Int64, read *int64, lastWriterWins bool) *int64 {
+	if lastWriterWins {
+		return read
+	}
+	return state.ValueInt64Pointer()
+}
@@ -6,17 +6,16 @@ package provider
 import (
 	"context"
 	"crypto/tls"
-	"fmt"
-	"io"
-	"net/http"
-	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
@@ -28,18 +27,12 @@ import (
 // Ensure NsxtIntervlanRoutingProvider satisfies various provider interfaces.
 var _ provider.Provider = &NsxtIntervlanRoutingProvider{}
 
-var Client http.Client
-var Auth AuthResponse
-var Host string
-
-type AuthResponse struct {
-	Session   string
-	Path      string
-	Secure    bool
-	HttpOnly  bool
-	SameSite  string
-	XsrfToken string
-}
+// clients tracks every client configured by this process so that their NSX-T
+// sessions can be destroyed on shutdown.
+var (
+	clientsMu sync.Mutex
+	clients   []*client.Client
+)
 
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
@@ -69,6 +62,48 @@ type NsxtIntervlanRoutingProviderModel struct {
 	NsxtUsername types.String `tfsdk:"username"`
 	NsxtPassword types.String `tfsdk:"password"`
 	NsxtHost     types.String `tfsdk:"host"`
+	NsxtHosts    types.List   `tfsdk:"hosts"`
+
+	NsxtClientAuthCertFile types.String `tfsdk:"client_auth_cert_file"`
+	NsxtClientAuthKeyFile  types.String `tfsdk:"client_auth_key_file"`
+	NsxtClientAuthCert     types.String `tfsdk:"client_auth_cert"`
+	NsxtClientAuthKey      types.String `tfsdk:"client_auth_key"`
+
+	NsxtCAFile                types.String `tfsdk:"ca_file"`
+	NsxtCA                    types.String `tfsdk:"ca_pem"`
+	NsxtServerCertFingerprint types.String `tfsdk:"server_cert_fingerprint"`
+	NsxtProxyURL              types.String `tfsdk:"proxy_url"`
+
+	NsxtMaxRetries         types.Int64 `tfsdk:"max_retries"`
+	NsxtRetryMinDelay      types.Int64 `tfsdk:"retry_min_delay"`
+	NsxtRetryMaxDelay      types.Int64 `tfsdk:"retry_max_delay"`
+	NsxtRetryOnStatusCodes types.List  `tfsdk:"retry_on_status_codes"`
+
+	NsxtLastWriterWins types.Bool `tfsdk:"last_writer_wins"`
+
+	NsxtRealizationTimeout types.Int64 `tfsdk:"realization_timeout"`
+
+	NsxtGlobalManager types.Bool `tfsdk:"global_manager"`
+
+	NsxtDefaultTags []Tag `tfsdk:"default_tags"`
+
+	Context *policyContextModel `tfsdk:"context"`
+}
+
+// NsxtIntervlanRoutingProviderData is made available to resources and data
+// sources by Configure.
+type NsxtIntervlanRoutingProviderData struct {
+	Client *client.Client
+
+	// LastWriterWins disables optimistic concurrency checks on updates.
+	LastWriterWins bool
+
+	// RealizationTimeout is how long to wait for NSX to realize segment
+	// ports after they are created or updated. Zero disables waiting.
+	RealizationTimeout time.Duration
+
+	// DefaultTags are added to every segment port the provider manages.
+	DefaultTags []client.Tag
 }
 
 func (p *NsxtIntervlanRoutingProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
@@ -76,7 +111,7 @@ func (p *NsxtIntervlanRoutingProvider) Schema(ctx context.Context, req provider.
 		Attributes: map[string]schema.Attribute{
 			"allow_insecure": schema.BoolAttribute{
 				Optional:    true,
-				Description: "Allow insecure SSL connections",
+				Description: "Allow insecure SSL connections. Conflicts with ca_file, ca_pem and server_cert_fingerprint.",
 			},
 			"username": schema.StringAttribute{
 				Optional:    true,
@@ -91,8 +126,93 @@ func (p *NsxtIntervlanRoutingProvider) Schema(ctx context.Context, req provider.
 				Optional:    true,
 				Description: "The hostname or IP address of the NSX API.",
 			},
+			"hosts": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Hostnames or IP addresses of NSX Manager nodes to fail over to, in order, when host is unreachable or unhealthy. If host is not set the first node is used instead.",
+			},
+			"client_auth_cert_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to the PEM encoded client certificate used to authenticate as an NSX principal identity. Replaces username and password authentication.",
+			},
+			"client_auth_key_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to the PEM encoded private key of the client certificate.",
+			},
+			"client_auth_cert": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM encoded client certificate used to authenticate as an NSX principal identity. Conflicts with client_auth_cert_file.",
+			},
+			"client_auth_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "PEM encoded private key of the client certificate. Conflicts with client_auth_key_file.",
+			},
+			"ca_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a PEM encoded bundle of the CA certificates to verify the NSX Manager's certificate against, instead of the system roots.",
+			},
+			"ca_pem": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM encoded bundle of the CA certificates to verify the NSX Manager's certificate against, instead of the system roots. Conflicts with ca_file.",
+			},
+			"server_cert_fingerprint": schema.StringAttribute{
+				Optional:    true,
+				Description: "SHA-256 fingerprint of the NSX Manager's certificate, in hex with or without colons. Only a certificate with this fingerprint is trusted, even if it is self-signed. Conflicts with ca_file and ca_pem.",
+			},
+			"proxy_url": schema.StringAttribute{
+				Optional:    true,
+				Description: "URL of an HTTP proxy to connect to NSX through, such as http://proxy.example.com:3128. Hosts listed in NO_PROXY are connected to directly. Defaults to the HTTPS_PROXY environment variable.",
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of times a request is retried when NSX is busy. A 409 Conflict is retried when NSX reports that the object is busy, whatever retry_on_status_codes is set to. Defaults to 4.",
+			},
+			"retry_min_delay": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Delay in milliseconds before the first retry. The delay doubles on each subsequent retry. Defaults to 500.",
+			},
+			"retry_max_delay": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum delay in milliseconds between retries, including delays requested by NSX with a Retry-After header. Defaults to 30000.",
+			},
+			"retry_on_status_codes": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.Int64Type,
+				Description: "HTTP status codes which cause a request to be retried. Each must be between 400 and 599. Defaults to 429 and 503.",
+			},
+			"last_writer_wins": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Overwrite changes made to a segment port outside of Terraform when updating it, instead of failing with a revision conflict. Defaults to false.",
+			},
+			"realization_timeout": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Time in seconds to wait for NSX to realize a segment port after creating or updating it. Set to 0 to return as soon as NSX accepts the change. Defaults to 300.",
+			},
+			"global_manager": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Set to true when host is an NSX Federation Global Manager, to manage the ports of global segments under /global-infra. Projects and VPCs are not supported by the Global Manager. Defaults to false.",
+			},
+			"default_tags": schema.SetNestedAttribute{
+				Optional:    true,
+				Description: "NSX tags added to every segment port the provider manages, unless the port has a tag with the same scope, or the same tag for a default tag without a scope. Default tags are not shown in the tags of the ports.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"scope": schema.StringAttribute{
+							Optional:    true,
+							Description: "Scope of the tag",
+						},
+						"tag": schema.StringAttribute{
+							Required:    true,
+							Description: "Value of the tag",
+						},
+					},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"context": providerPolicyContextBlock(),
 		},
-		Blocks:      map[string]schema.Block{},
 		Description: "Interface with the NSX API.",
 	}
 }
@@ -117,6 +237,14 @@ func (p *NsxtIntervlanRoutingProvider) Configure(ctx context.Context, req provid
 				"Either target apply the source of the value first, set the value statically in the configuration, or use the NSXT_HOST environment variable.",
 		)
 	}
+	if config.NsxtHosts.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("hosts"),
+			"Unknown NSX InterVLAN Routing hosts",
+			"The provider cannot create the NSX InterVLAN Routing client as there is an unknown configuration value for the API hosts. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the NSXT_HOSTS environment variable.",
+		)
+	}
 	if config.NsxtUsername.IsUnknown() {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("username"),
@@ -133,6 +261,70 @@ func (p *NsxtIntervlanRoutingProvider) Configure(ctx context.Context, req provid
 				"Either target apply the source of the value first, set the value statically in the configuration, or use the NSXT_PASSWORD environment variable.",
 		)
 	}
+	if config.NsxtClientAuthCertFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_auth_cert_file"),
+			"Unknown NSX InterVLAN Routing client certificate file",
+			"The provider cannot create the NSX InterVLAN Routing client as there is an unknown configuration value for the client certificate file. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the NSXT_CLIENT_AUTH_CERT_FILE environment variable.",
+		)
+	}
+	if config.NsxtClientAuthKeyFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_auth_key_file"),
+			"Unknown NSX InterVLAN Routing client key file",
+			"The provider cannot create the NSX InterVLAN Routing client as there is an unknown configuration value for the client key file. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the NSXT_CLIENT_AUTH_KEY_FILE environment variable.",
+		)
+	}
+	if config.NsxtClientAuthCert.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_auth_cert"),
+			"Unknown NSX InterVLAN Routing client certificate",
+			"The provider cannot create the NSX InterVLAN Routing client as there is an unknown configuration value for the client certificate. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the NSXT_CLIENT_AUTH_CERT environment variable.",
+		)
+	}
+	if config.NsxtClientAuthKey.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_auth_key"),
+			"Unknown NSX InterVLAN Routing client key",
+			"The provider cannot create the NSX InterVLAN Routing client as there is an unknown configuration value for the client key. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the NSXT_CLIENT_AUTH_KEY environment variable.",
+		)
+	}
+	if config.NsxtCAFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ca_file"),
+			"Unknown NSX InterVLAN Routing CA certificate file",
+			"The provider cannot create the NSX InterVLAN Routing client as there is an unknown configuration value for the CA certificate file. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the NSXT_CA_FILE environment variable.",
+		)
+	}
+	if config.NsxtCA.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ca_pem"),
+			"Unknown NSX InterVLAN Routing CA certificates",
+			"The provider cannot create the NSX InterVLAN Routing client as there is an unknown configuration value for the CA certificates. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the NSXT_CA environment variable.",
+		)
+	}
+	if config.NsxtServerCertFingerprint.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("server_cert_fingerprint"),
+			"Unknown NSX InterVLAN Routing server certificate fingerprint",
+			"The provider cannot create the NSX InterVLAN Routing client as there is an unknown configuration value for the server certificate fingerprint. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the NSXT_SERVER_CERT_FINGERPRINT environment variable.",
+		)
+	}
+	if config.NsxtProxyURL.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("proxy_url"),
+			"Unknown NSX InterVLAN Routing proxy URL",
+			"The provider cannot create the NSX InterVLAN Routing client as there is an unknown configuration value for the proxy URL. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the NSXT_PROXY_URL environment variable.",
+		)
+	}
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -141,8 +333,20 @@ func (p *NsxtIntervlanRoutingProvider) Configure(ctx context.Context, req provid
 	// with Terraform configuration value if set.
 	insecure := os.Getenv("NSXT_INSECURE")
 	hostname := os.Getenv("NSXT_HOSTNAME")
+	var nodes []string
+	if v := os.Getenv("NSXT_HOSTS"); v != "" {
+		nodes = strings.Split(v, ",")
+	}
 	username := os.Getenv("NSXT_USERNAME")
 	password := os.Getenv("NSXT_PASSWORD")
+	certFile := os.Getenv("NSXT_CLIENT_AUTH_CERT_FILE")
+	keyFile := os.Getenv("NSXT_CLIENT_AUTH_KEY_FILE")
+	certPEM := os.Getenv("NSXT_CLIENT_AUTH_CERT")
+	keyPEM := os.Getenv("NSXT_CLIENT_AUTH_KEY")
+	caFile := os.Getenv("NSXT_CA_FILE")
+	caPEM := os.Getenv("NSXT_CA")
+	fingerprint := os.Getenv("NSXT_SERVER_CERT_FINGERPRINT")
+	proxyURL := os.Getenv("NSXT_PROXY_URL")
 
 	if !config.NsxtInsecure.IsNull() {
 		insecure = config.NsxtInsecure.String()
@@ -150,12 +354,47 @@ func (p *NsxtIntervlanRoutingProvider) Configure(ctx context.Context, req provid
 	if !config.NsxtHost.IsNull() {
 		hostname = config.NsxtHost.ValueString()
 	}
+	if !config.NsxtHosts.IsNull() {
+		nodes = nil
+		resp.Diagnostics.Append(config.NsxtHosts.ElementsAs(ctx, &nodes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 	if !config.NsxtUsername.IsNull() {
 		username = config.NsxtUsername.ValueString()
 	}
 	if !config.NsxtPassword.IsNull() {
 		password = config.NsxtPassword.ValueString()
 	}
+	if !config.NsxtClientAuthCertFile.IsNull() {
+		certFile = config.NsxtClientAuthCertFile.ValueString()
+	}
+	if !config.NsxtClientAuthKeyFile.IsNull() {
+		keyFile = config.NsxtClientAuthKeyFile.ValueString()
+	}
+	if !config.NsxtClientAuthCert.IsNull() {
+		certPEM = config.NsxtClientAuthCert.ValueString()
+	}
+	if !config.NsxtClientAuthKey.IsNull() {
+		keyPEM = config.NsxtClientAuthKey.ValueString()
+	}
+	if !config.NsxtCAFile.IsNull() {
+		caFile = config.NsxtCAFile.ValueString()
+	}
+	if !config.NsxtCA.IsNull() {
+		caPEM = config.NsxtCA.ValueString()
+	}
+	if !config.NsxtServerCertFingerprint.IsNull() {
+		fingerprint = config.NsxtServerCertFingerprint.ValueString()
+	}
+	if !config.NsxtProxyURL.IsNull() {
+		proxyURL = config.NsxtProxyURL.ValueString()
+	}
+
+	// Certificate (principal identity) authentication replaces the username
+	// and password entirely.
+	certAuth := certFile != "" || keyFile != "" || certPEM != "" || keyPEM != ""
 
 	// If any of the expected configurations are missing, return
 	// errors with provider-specific guidance.
@@ -170,6 +409,9 @@ func (p *NsxtIntervlanRoutingProvider) Configure(ctx context.Context, req provid
 		)
 		//insecure = "false"
 	}
+	if hostname == "" && len(nodes) > 0 {
+		hostname, nodes = nodes[0], nodes[1:]
+	}
 	if hostname == "" {
 		resp.Diagnostics.AddAttributeWarning(
 			path.Root("host"),
@@ -180,7 +422,7 @@ func (p *NsxtIntervlanRoutingProvider) Configure(ctx context.Context, req provid
 		)
 		hostname = "127.0.0.1"
 	}
-	if username == "" {
+	if username == "" && !certAuth {
 		resp.Diagnostics.AddAttributeWarning(
 			path.Root("username"),
 			"Missing NSX-T API username (using default value: admin)",
@@ -190,7 +432,7 @@ func (p *NsxtIntervlanRoutingProvider) Configure(ctx context.Context, req provid
 		)
 		username = "admin"
 	}
-	if password == "" {
+	if password == "" && !certAuth {
 		resp.Diagnostics.AddAttributeWarning(
 			path.Root("password"),
 			"Missing NSX-T API port (using default value: password)",
@@ -208,124 +450,350 @@ func (p *NsxtIntervlanRoutingProvider) Configure(ctx context.Context, req provid
 
 	// Create the configuration for the NSX-T API Client
 	isInsecure, _ := strconv.ParseBool(insecure)
-	resp.Diagnostics.AddWarning("Hostname provided: ", hostname)
-	fmt.Println(hostname)
-	Host = ""
-	if !strings.HasPrefix(hostname, "https://") ||
-		!strings.HasPrefix(hostname, "http://") {
-		Host = "https://" + hostname
+	tflog.Debug(ctx, "Using NSX-T Manager API host", map[string]any{"host": hostname})
+	host := nodeURL(hostname)
+
+	policy, diags := retryPolicy(ctx, config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	globalManager, _ := strconv.ParseBool(os.Getenv("NSXT_GLOBAL_MANAGER"))
+	if !config.NsxtGlobalManager.IsNull() && !config.NsxtGlobalManager.IsUnknown() {
+		globalManager = config.NsxtGlobalManager.ValueBool()
 	}
 
-	creds := url.Values{}
-	creds.Set("j_username", username)
-	creds.Set("j_password", password)
-	enc_creds := creds.Encode()
+	pc := config.Context.merge(client.PolicyContext{GlobalManager: globalManager})
+	if err := pc.Validate(); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("context"), "Invalid NSX Context", err.Error())
+		return
+	}
 
-	// Example client configuration for data sources and resources
-	tr := &http.Transport{}
-	if isInsecure {
-		tr = &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	opts := []client.ClientOption{
+		client.WithInsecure(isInsecure),
+		client.WithTimeout(10 * time.Second),
+		client.WithRetryPolicy(policy),
+		client.WithPolicyContext(pc),
+	}
+	if len(nodes) > 0 {
+		failover := make([]string, 0, len(nodes))
+		for _, node := range nodes {
+			failover = append(failover, nodeURL(strings.TrimSpace(node)))
 		}
+		tflog.Debug(ctx, "Using NSX-T Manager API failover hosts", map[string]any{"hosts": failover})
+		opts = append(opts, client.WithFailoverNodes(failover...))
 	}
-	Client := &http.Client{
-		Transport: tr,
-		Timeout:   10 * time.Second,
+	if certAuth {
+		cert, diags := loadClientCertificate(certFile, keyFile, certPEM, keyPEM)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		opts = append(opts, client.WithClientCertificate(cert))
+		username, password = "", ""
 	}
-	request, err := http.NewRequest(
-		"POST",
-		Host+"/api/session/create",
-		strings.NewReader(enc_creds))
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error occurred configuring the client parameters",
-			"An unexpected error occurred when configuring the NSX-T API client. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"NSX-T Client Error: "+err.Error(),
+	if isInsecure && (caFile != "" || caPEM != "" || fingerprint != "") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("allow_insecure"),
+			"Conflicting NSX-T certificate verification",
+			"allow_insecure can't be combined with ca_file, ca_pem or server_cert_fingerprint, which configure how the NSX Manager's certificate is verified instead of skipping verification.",
 		)
 		return
 	}
+	if fingerprint != "" && (caFile != "" || caPEM != "") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("server_cert_fingerprint"),
+			"Conflicting NSX-T certificate verification",
+			"server_cert_fingerprint can't be combined with ca_file or ca_pem, as the pinned certificate is trusted without verifying it against the CA certificates.",
+		)
+		return
+	}
+	if caFile != "" || caPEM != "" {
+		ca, diags := loadCACertificates(caFile, caPEM)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		opts = append(opts, client.WithCACertificates(ca))
+	}
+	if fingerprint != "" {
+		if _, err := client.ParseCertificateFingerprint(fingerprint); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("server_cert_fingerprint"), "Invalid NSX-T server certificate fingerprint", err.Error())
+			return
+		}
+		opts = append(opts, client.WithCertificateFingerprint(fingerprint))
+	}
+	if proxyURL != "" {
+		tflog.Debug(ctx, "Using HTTP proxy for NSX-T Manager API", map[string]any{"proxy_url": proxyURL})
+		opts = append(opts, client.WithProxy(proxyURL))
+	}
 
-	response, err := Client.Do(request)
+	nsxClient, err := client.NewClient(host, username, password, opts...)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to Create NSX-T API Client",
-			"An unexpected error occurred when creating the NSX-T API client. "+
+			"Error occurred configuring the client parameters",
+			"An unexpected error occurred when configuring the NSX-T API client. "+
 				"If the error is not clear, please contact the provider developers.\n\n"+
 				"NSX-T Client Error: "+err.Error(),
 		)
 		return
 	}
-	defer response.Body.Close()
 
-	if response.StatusCode == http.StatusOK {
-		body, err := io.ReadAll(response.Body)
-		if err != nil {
+	// Create the session up front so that bad credentials fail early rather
+	// than on the first resource operation.
+	if nsxClient.Session != nil {
+		if err := nsxClient.Session.Create(ctx); err != nil {
 			resp.Diagnostics.AddError(
-				"Error reading the API response",
-				"An unexpected error occurred when reading the NSX-T API client response. "+
+				"Unable to Create NSX-T API Client",
+				"An unexpected error occurred when authenticating the NSX-T API client. "+
 					"If the error is not clear, please contact the provider developers.\n\n"+
 					"NSX-T Client Error: "+err.Error(),
 			)
+			tflog.Info(ctx, "Configured NSX-T client", map[string]any{"success": false})
 			return
 		}
-		Auth := convertBodyToMap(string(body))
-		_ = Auth
-
-		// Make the Inventory client available during DataSource and Resource
-		// type Configure methods.
-		resp.DataSourceData = Client
-		resp.ResourceData = Client
+		registerClient(nsxClient)
+	}
 
-		tflog.Info(ctx, "Configured NSX-T client", map[string]any{"success": true})
-	} else {
-		resp.Diagnostics.AddError(
-			"NSX-T API Client returned a non-200 status code",
-			"The NSX-T API Client returned a non-200 status code. The response returned "+
-				"indicates an error authenticating the client.\n\n"+
-				"NSX-T Client Error: "+err.Error(),
-		)
-		tflog.Info(ctx, "Configured NSX-T client", map[string]any{"success": false})
+	lastWriterWins, _ := strconv.ParseBool(os.Getenv("NSXT_LAST_WRITER_WINS"))
+	if !config.NsxtLastWriterWins.IsNull() && !config.NsxtLastWriterWins.IsUnknown() {
+		lastWriterWins = config.NsxtLastWriterWins.ValueBool()
+	}
 
+	realizationTimeout, err := int64FromEnv("NSXT_REALIZATION_TIMEOUT", int64(defaultRealizationTimeout/time.Second))
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("realization_timeout"), "Invalid NSXT_REALIZATION_TIMEOUT environment variable", err.Error())
+		return
+	}
+	if !config.NsxtRealizationTimeout.IsNull() && !config.NsxtRealizationTimeout.IsUnknown() {
+		realizationTimeout = config.NsxtRealizationTimeout.ValueInt64()
+	}
+	if realizationTimeout < 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("realization_timeout"), "Invalid NSX-T realization timeout", "realization_timeout cannot be negative.")
 		return
 	}
+
+	// Make the NSX-T client available during DataSource and Resource
+	// type Configure methods.
+	data := &NsxtIntervlanRoutingProviderData{
+		Client:             nsxClient,
+		LastWriterWins:     lastWriterWins,
+		RealizationTimeout: time.Duration(realizationTimeout) * time.Second,
+		DefaultTags:        tagsToClient(config.NsxtDefaultTags),
+	}
+	resp.DataSourceData = data
+	resp.ResourceData = data
+
+	tflog.Info(ctx, "Configured NSX-T client", map[string]any{"success": true})
+}
+
+// nodeURL returns the URL of the NSX Manager API at hostname, which defaults
+// to HTTPS.
+func nodeURL(hostname string) string {
+	if !strings.HasPrefix(hostname, "https://") &&
+		!strings.HasPrefix(hostname, "http://") {
+		return "https://" + hostname
+	}
+	return hostname
+}
+
+// retryPolicy builds the client retry policy from the provider configuration,
+// falling back to environment variables and then the client defaults.
+func retryPolicy(ctx context.Context, config NsxtIntervlanRoutingProviderModel) (client.RetryPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	policy := client.DefaultRetryPolicy()
+
+	maxRetries, err := int64FromEnv("NSXT_MAX_RETRIES", int64(policy.MaxRetries))
+	if err != nil {
+		diags.AddAttributeError(path.Root("max_retries"), "Invalid NSXT_MAX_RETRIES environment variable", err.Error())
+	}
+	minDelay, err := int64FromEnv("NSXT_RETRY_MIN_DELAY", policy.MinDelay.Milliseconds())
+	if err != nil {
+		diags.AddAttributeError(path.Root("retry_min_delay"), "Invalid NSXT_RETRY_MIN_DELAY environment variable", err.Error())
+	}
+	maxDelay, err := int64FromEnv("NSXT_RETRY_MAX_DELAY", policy.MaxDelay.Milliseconds())
+	if err != nil {
+		diags.AddAttributeError(path.Root("retry_max_delay"), "Invalid NSXT_RETRY_MAX_DELAY environment variable", err.Error())
+	}
+
+	if !config.NsxtMaxRetries.IsNull() && !config.NsxtMaxRetries.IsUnknown() {
+		maxRetries = config.NsxtMaxRetries.ValueInt64()
+	}
+	if !config.NsxtRetryMinDelay.IsNull() && !config.NsxtRetryMinDelay.IsUnknown() {
+		minDelay = config.NsxtRetryMinDelay.ValueInt64()
+	}
+	if !config.NsxtRetryMaxDelay.IsNull() && !config.NsxtRetryMaxDelay.IsUnknown() {
+		maxDelay = config.NsxtRetryMaxDelay.ValueInt64()
+	}
+	if !config.NsxtRetryOnStatusCodes.IsNull() && !config.NsxtRetryOnStatusCodes.IsUnknown() {
+		var codes []int64
+		diags.Append(config.NsxtRetryOnStatusCodes.ElementsAs(ctx, &codes, false)...)
+		policy.RetryableStatusCodes = nil
+		for _, code := range codes {
+			if code < 400 || code > 599 {
+				diags.AddAttributeError(path.Root("retry_on_status_codes"), "Invalid NSX-T retry status code", "retry_on_status_codes must only contain error status codes between 400 and 599, got "+strconv.FormatInt(code, 10)+".")
+				continue
+			}
+			policy.RetryableStatusCodes = append(policy.RetryableStatusCodes, int(code))
+		}
+	}
+
+	if maxRetries < 0 {
+		diags.AddAttributeError(path.Root("max_retries"), "Invalid NSX-T max retries", "max_retries cannot be negative.")
+	}
+	if minDelay < 0 || maxDelay < 0 {
+		diags.AddAttributeError(path.Root("retry_min_delay"), "Invalid NSX-T retry delay", "retry_min_delay and retry_max_delay cannot be negative.")
+	}
+	if minDelay > maxDelay {
+		diags.AddAttributeError(path.Root("retry_max_delay"), "Invalid NSX-T retry delay", "retry_max_delay cannot be less than retry_min_delay.")
+	}
+
+	policy.MaxRetries = int(maxRetries)
+	policy.MinDelay = time.Duration(minDelay) * time.Millisecond
+	policy.MaxDelay = time.Duration(maxDelay) * time.Millisecond
+	return policy, diags
+}
+
+// int64FromEnv returns the integer value of an environment variable, or def
+// if it is not set.
+func int64FromEnv(name string, def int64) (int64, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return def, nil
+	}
+	return strconv.ParseInt(v, 10, 64)
 }
 
-func convertBodyToMap(bodyString string) AuthResponse {
-	dataMap := make(map[string]string)
-	parts := strings.Split(bodyString, ":")
-	for _, part := range parts {
-		kv := strings.SplitN(part, "=", 2) // Split only on the first '='
-		if len(kv) == 2 {
-			key := strings.TrimSpace(kv[0])
-			value := strings.TrimSpace(kv[1])
-			dataMap[key] = value
+// loadClientCertificate loads the principal identity certificate and key,
+// each of which may be provided either as a file or as inline PEM.
+func loadClientCertificate(certFile string, keyFile string, certPEM string, keyPEM string) (tls.Certificate, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if certFile != "" && certPEM != "" {
+		diags.AddAttributeError(
+			path.Root("client_auth_cert"),
+			"Conflicting NSX-T client certificate configuration",
+			"Only one of client_auth_cert_file and client_auth_cert can be set.",
+		)
+	}
+	if keyFile != "" && keyPEM != "" {
+		diags.AddAttributeError(
+			path.Root("client_auth_key"),
+			"Conflicting NSX-T client key configuration",
+			"Only one of client_auth_key_file and client_auth_key can be set.",
+		)
+	}
+	if certFile == "" && certPEM == "" {
+		diags.AddAttributeError(
+			path.Root("client_auth_cert_file"),
+			"Missing NSX-T client certificate",
+			"A client key was configured without a client certificate. "+
+				"Set client_auth_cert_file or client_auth_cert, or use the NSXT_CLIENT_AUTH_CERT_FILE or NSXT_CLIENT_AUTH_CERT environment variables.",
+		)
+	}
+	if keyFile == "" && keyPEM == "" {
+		diags.AddAttributeError(
+			path.Root("client_auth_key_file"),
+			"Missing NSX-T client key",
+			"A client certificate was configured without a client key. "+
+				"Set client_auth_key_file or client_auth_key, or use the NSXT_CLIENT_AUTH_KEY_FILE or NSXT_CLIENT_AUTH_KEY environment variables.",
+		)
+	}
+	if diags.HasError() {
+		return tls.Certificate{}, diags
+	}
+
+	if certFile != "" {
+		buf, err := os.ReadFile(certFile)
+		if err != nil {
+			diags.AddAttributeError(path.Root("client_auth_cert_file"), "Unable to read NSX-T client certificate", err.Error())
+			return tls.Certificate{}, diags
+		}
+		certPEM = string(buf)
+	}
+	if keyFile != "" {
+		buf, err := os.ReadFile(keyFile)
+		if err != nil {
+			diags.AddAttributeError(path.Root("client_auth_key_file"), "Unable to read NSX-T client key", err.Error())
+			return tls.Certificate{}, diags
 		}
+		keyPEM = string(buf)
+	}
+
+	cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	if err != nil {
+		diags.AddError(
+			"Invalid NSX-T client certificate",
+			"The client certificate and key could not be loaded.\n\n"+
+				"Error: "+err.Error(),
+		)
 	}
+	return cert, diags
+}
+
+// loadCACertificates returns the PEM encoded CA bundle, provided either as a
+// file or inline.
+func loadCACertificates(caFile string, caPEM string) ([]byte, diag.Diagnostics) {
+	var diags diag.Diagnostics
 
-	is_secure, _ := strconv.ParseBool(dataMap["secure"])
-	is_http_only, _ := strconv.ParseBool(dataMap["http_only"])
+	if caFile != "" && caPEM != "" {
+		diags.AddAttributeError(
+			path.Root("ca_pem"),
+			"Conflicting NSX-T CA configuration",
+			"Only one of ca_file and ca_pem can be set.",
+		)
+		return nil, diags
+	}
+	if caFile == "" {
+		return []byte(caPEM), diags
+	}
 
-	response := AuthResponse{
-		Session:   dataMap["JSESSIONID"],
-		Path:      dataMap["Path"],
-		Secure:    is_secure,
-		HttpOnly:  is_http_only,
-		SameSite:  dataMap["SameSite"],
-		XsrfToken: dataMap["x-xsrf-token"],
+	buf, err := os.ReadFile(caFile)
+	if err != nil {
+		diags.AddAttributeError(path.Root("ca_file"), "Unable to read NSX-T CA certificates", err.Error())
 	}
+	return buf, diags
+}
+
+func registerClient(c *client.Client) {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	clients = append(clients, c)
+}
+
+// Shutdown destroys the NSX-T sessions of every client configured by this
+// process. It is called once the provider server has stopped.
+func Shutdown(ctx context.Context) {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
 
-	return response
+	for _, c := range clients {
+		// Sessions expire on their own, so failing to destroy one is not fatal.
+		_ = c.CloseSession(ctx)
+	}
+	clients = nil
 }
 
 func (p *NsxtIntervlanRoutingProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
+		NewSegmentResource,
 		NewSegmentPortResource,
+		NewSegmentPortTrunkResource,
+		NewStaticRouteResource,
+		NewTier1InterfaceResource,
 	}
 }
 
 func (p *NsxtIntervlanRoutingProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
+		NewSegmentPortDataSource,
 		NewSegmentPortsDataSource,
+		NewSegmentPortSearchDataSource,
+		NewVirtualMachineDataSource,
+		NewVifsDataSource,
+		NewSegmentsDataSource,
+		NewTransportZonesDataSource,
 	}
 }
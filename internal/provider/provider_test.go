@@ -4,23 +4,20 @@
 package provider
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
-)
-
-const (
-	// providerConfig is a shared configuration to combine with the actual
-	// test configuration so the nsxt-intervlan-routing client is properly configured.
-	providerConfig = `terraform {
-  required_providers {
-    nsxt-intervlan-routing = {
-      source = "technofish-au/nsxt-intervlan-routing"
-    }
-  }
-}
-
-provider "nsxt-intervlan-routing" {}
-`
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/internal/nsxtest"
 )
 
 // testAccProtoV6ProviderFactories is used to instantiate a provider during acceptance testing.
@@ -30,11 +27,156 @@ var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServe
 	"nsxt-intervlan-routing": providerserver.NewProtocol6WithError(New("test")()),
 }
 
-// testAccProtoV6ProviderFactoriesWithEcho includes the echo provider alongside the scaffolding provider.
-// It allows for testing assertions on data returned by an ephemeral resource during Open.
-// The echoprovider is used to arrange tests by echoing ephemeral data into the Terraform state.
-// This lets the data be referenced in test assertions with state checks.
-// var testAccProtoV6ProviderFactoriesWithEcho = map[string]func() (tfprotov6.ProviderServer, error){
-// 	"scaffolding": providerserver.NewProtocol6WithError(New("test")()),
-// 	"echo":        echoprovider.NewProviderServer(),
-// }
+// testAccNewServer starts a fake NSX Manager for an acceptance test, with
+// the segments the test configurations attach their ports to.
+func testAccNewServer(t *testing.T) *nsxtest.Server {
+	t.Helper()
+
+	srv := nsxtest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddSegment("parent-segment")
+	srv.AddSegment("child-segment")
+	return srv
+}
+
+// testAccProviderConfig is a shared configuration to combine with the actual
+// test configuration so the nsxt-intervlan-routing client talks to srv.
+func testAccProviderConfig(srv *nsxtest.Server) string {
+	return fmt.Sprintf(`
+provider "nsxt-intervlan-routing" {
+  host     = %q
+  username = %q
+  password = %q
+}
+`, srv.URL, nsxtest.Username, nsxtest.Password)
+}
+
+// testAccLastWriterWinsConfig is testAccProviderConfig with last_writer_wins
+// set, so that updates overwrite changes made outside of Terraform.
+func testAccLastWriterWinsConfig(srv *nsxtest.Server) string {
+	return fmt.Sprintf(`
+provider "nsxt-intervlan-routing" {
+  host             = %q
+  username         = %q
+  password         = %q
+  last_writer_wins = true
+}
+`, srv.URL, nsxtest.Username, nsxtest.Password)
+}
+
+// testAccCheckObjectField checks that a field of the object at policyPath
+// has the expected value in NSX, such as one the provider doesn't model.
+func testAccCheckObjectField(srv *nsxtest.Server, policyPath string, field string, expected any) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		if value, _ := srv.ObjectField(policyPath, field); value != expected {
+			return fmt.Errorf("expected %s of %s to be %v, got %v", field, policyPath, expected, value)
+		}
+		return nil
+	}
+}
+
+func TestAccProviderFailover(t *testing.T) {
+	srv := testAccNewServer(t)
+	down := httptest.NewServer(nil)
+	down.Close()
+
+	config := fmt.Sprintf(`
+provider "nsxt-intervlan-routing" {
+  host     = %q
+  hosts    = [%q]
+  username = %q
+  password = %q
+}
+`, down.URL, srv.URL, nsxtest.Username, nsxtest.Password) + testAccParentPortResource("parent")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: func(_ *terraform.State) error {
+					if _, ok := srv.SegmentPort("parent-segment", "parent-port"); !ok {
+						return fmt.Errorf("segment port parent-port was not created on the failover host")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccProviderRetryStatusCodes(t *testing.T) {
+	srv := testAccNewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "nsxt-intervlan-routing" {
+  host                  = %q
+  username              = %q
+  password              = %q
+  retry_on_status_codes = [409, 200]
+}
+`, srv.URL, nsxtest.Username, nsxtest.Password) + testAccParentPortResource("parent"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`between 400 and 599, got 200`),
+			},
+		},
+	})
+}
+
+func TestAccProviderCA(t *testing.T) {
+	srv := nsxtest.NewTLSServer()
+	t.Cleanup(srv.Close)
+	srv.AddSegment("parent-segment")
+
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	sum := sha256.Sum256(srv.Certificate().Raw)
+
+	config := func(tls string) string {
+		return fmt.Sprintf(`
+provider "nsxt-intervlan-routing" {
+  host     = %q
+  username = %q
+  password = %q
+  %s
+}
+`, srv.URL, nsxtest.Username, nsxtest.Password, tls) + testAccParentPortResource("parent")
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(""),
+				ExpectError: regexp.MustCompile(`certificate`),
+			},
+			{
+				Config:      config(fmt.Sprintf("server_cert_fingerprint = %q", strings.Repeat("00", sha256.Size))),
+				ExpectError: regexp.MustCompile(`does not match the pinned fingerprint`),
+			},
+			{
+				Config: config(fmt.Sprintf("ca_pem = %q", ca)),
+				Check:  resource.TestCheckResourceAttr("nsxt-intervlan-routing_segment_port.parent", "id", "parent-port"),
+			},
+			{
+				Config: config(fmt.Sprintf("server_cert_fingerprint = %q", hex.EncodeToString(sum[:]))),
+				Check:  resource.TestCheckResourceAttr("nsxt-intervlan-routing_segment_port.parent", "id", "parent-port"),
+			},
+			{
+				Config:      config(fmt.Sprintf("ca_pem = %q\n  server_cert_fingerprint = %q", ca, hex.EncodeToString(sum[:]))),
+				ExpectError: regexp.MustCompile(`Conflicting NSX-T certificate verification`),
+			},
+			{
+				Config:      config(fmt.Sprintf("ca_pem = %q\n  allow_insecure = true", ca)),
+				ExpectError: regexp.MustCompile(`Conflicting NSX-T certificate verification`),
+			},
+			{
+				Config:      config(fmt.Sprintf("server_cert_fingerprint = %q\n  allow_insecure = true", hex.EncodeToString(sum[:]))),
+				ExpectError: regexp.MustCompile(`Conflicting NSX-T certificate verification`),
+			},
+		},
+	})
+}
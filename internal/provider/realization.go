@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+const (
+	// defaultRealizationTimeout is used unless realization_timeout is set.
+	defaultRealizationTimeout = 5 * time.Minute
+	// pollInterval is how often NSX is polled while waiting for it to
+	// realize or delete objects.
+	pollInterval = time.Second
+)
+
+// waitForRealization waits up to timeout for NSX to realize every object in
+// intentPaths. A zero timeout doesn't wait at all.
+func waitForRealization(ctx context.Context, c *client.Client, timeout time.Duration, intentPaths ...string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if timeout <= 0 {
+		return diags
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for _, intentPath := range intentPaths {
+		if err := c.WaitForRealization(ctx, intentPath, pollInterval); err != nil {
+			detail := err.Error() + ". The change was accepted by NSX, but the port can't be used until it is realized."
+			if errors.Is(err, context.DeadlineExceeded) {
+				detail += " Increase realization_timeout in the provider configuration if NSX is slow to realize ports."
+			}
+			diags.AddError("Segment Port Not Realized", detail)
+			return diags
+		}
+	}
+	return diags
+}
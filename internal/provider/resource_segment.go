@@ -0,0 +1,430 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+var (
+	_ resource.Resource                = &segmentResource{}
+	_ resource.ResourceWithConfigure   = &segmentResource{}
+	_ resource.ResourceWithImportState = &segmentResource{}
+)
+
+func NewSegmentResource() resource.Resource {
+	return &segmentResource{}
+}
+
+// segmentResource manages a VLAN backed or overlay segment, which segment
+// ports are created on.
+type segmentResource struct {
+	client         *client.Client
+	lastWriterWins bool
+}
+
+type segmentResourceModel struct {
+	Id                types.String         `tfsdk:"id"`
+	DisplayName       types.String         `tfsdk:"display_name"`
+	Description       types.String         `tfsdk:"description"`
+	TransportZonePath types.String         `tfsdk:"transport_zone_path"`
+	VlanIds           []types.String       `tfsdk:"vlan_ids"`
+	ConnectivityPath  types.String         `tfsdk:"connectivity_path"`
+	Subnets           []segmentSubnetModel `tfsdk:"subnets"`
+	AdminState        types.String         `tfsdk:"admin_state"`
+	Tags              []Tag                `tfsdk:"tags"`
+
+	Path     types.String `tfsdk:"path"`
+	Revision types.Int64  `tfsdk:"revision"`
+
+	Context *policyContextModel `tfsdk:"context"`
+}
+
+type segmentSubnetModel struct {
+	GatewayAddress types.String   `tfsdk:"gateway_address"`
+	DhcpRanges     []types.String `tfsdk:"dhcp_ranges"`
+	Network        types.String   `tfsdk:"network"`
+}
+
+func (r *segmentResource) Configure(ctx context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*NsxtIntervlanRoutingProviderData)
+	if !ok {
+		tflog.Error(ctx, "Unable to prepare client")
+		return
+	}
+	r.client = data.Client
+	r.lastWriterWins = data.LastWriterWins
+}
+
+// Metadata returns the resource type name.
+func (r *segmentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_segment"
+}
+
+func (r *segmentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manage a VLAN backed or overlay segment.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description:         "Identifier of the segment.",
+				MarkdownDescription: "Identifier of the segment.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"display_name": schema.StringAttribute{
+				Description:         "Display name of the segment. Defaults to the id.",
+				MarkdownDescription: "Display name of the segment. Defaults to the `id`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description:         "Description of the segment.",
+				MarkdownDescription: "Description of the segment.",
+				Optional:            true,
+			},
+			"transport_zone_path": schema.StringAttribute{
+				Description:         "Policy path of the transport zone of the segment, such as /infra/sites/default/enforcement-points/default/transport-zones/<id>. Required for VLAN backed segments. Changing it creates a new segment.",
+				MarkdownDescription: "Policy path of the transport zone of the segment, such as `/infra/sites/default/enforcement-points/default/transport-zones/<id>`. Required for VLAN backed segments. Changing it creates a new segment.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vlan_ids": schema.ListAttribute{
+				Description:         "VLAN IDs or ranges, such as 100 or 200-300, backing a segment in a VLAN transport zone.",
+				MarkdownDescription: "VLAN IDs or ranges, such as `100` or `200-300`, backing a segment in a VLAN transport zone.",
+				Optional:            true,
+				ElementType:         types.StringType,
+				Validators:          []validator.List{vlanRangesValidator{}},
+			},
+			"connectivity_path": schema.StringAttribute{
+				Description:         "Policy path of the tier-1 gateway the segment is connected to, such as /infra/tier-1s/<id>.",
+				MarkdownDescription: "Policy path of the tier-1 gateway the segment is connected to, such as `/infra/tier-1s/<id>`.",
+				Optional:            true,
+			},
+			"subnets": schema.ListNestedAttribute{
+				Description:         "Subnets of the segment, which its gateway routes between.",
+				MarkdownDescription: "Subnets of the segment, which its gateway routes between.",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"gateway_address": schema.StringAttribute{
+							Description:         "Gateway address of the subnet in CIDR notation, such as 10.0.1.1/24.",
+							MarkdownDescription: "Gateway address of the subnet in CIDR notation, such as `10.0.1.1/24`.",
+							Required:            true,
+						},
+						"dhcp_ranges": schema.ListAttribute{
+							Description:         "Ranges of addresses DHCP assigns, such as 10.0.1.100-10.0.1.200.",
+							MarkdownDescription: "Ranges of addresses DHCP assigns, such as `10.0.1.100-10.0.1.200`.",
+							Optional:            true,
+							ElementType:         types.StringType,
+						},
+						"network": schema.StringAttribute{
+							Description:         "Network address of the subnet.",
+							MarkdownDescription: "Network address of the subnet.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"admin_state": schema.StringAttribute{
+				Description:         "Admin state of the segment. Either UP or DOWN. Defaults to UP.",
+				MarkdownDescription: "Admin state of the segment. Either `UP` or `DOWN`. Defaults to `UP`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tags": resourceTagsAttribute("segment"),
+			"path": schema.StringAttribute{
+				Description:         "Policy path of the segment, which segment ports and tier-1 interfaces refer to it by.",
+				MarkdownDescription: "Policy path of the segment, which segment ports and tier-1 interfaces refer to it by.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"revision": schema.Int64Attribute{
+				Description:         "NSX revision of the segment when it was last read. Updates are rejected if the segment has since been modified outside of Terraform.",
+				MarkdownDescription: "NSX revision of the segment when it was last read. Updates are rejected if the segment has since been modified outside of Terraform.",
+				Computed:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"context": resourcePolicyContextBlock(),
+		},
+	}
+}
+
+// toClient converts the Terraform model to the segment sent to NSX. system
+// are the tags NSX applied to the segment itself, which are kept.
+func (m segmentResourceModel) toClient(system []client.Tag) client.Segment {
+	segment := client.Segment{
+		AdminState:        m.AdminState.ValueString(),
+		ConnectivityPath:  m.ConnectivityPath.ValueString(),
+		Description:       m.Description.ValueString(),
+		DisplayName:       m.DisplayName.ValueString(),
+		Id:                m.Id.ValueString(),
+		ResourceType:      "Segment",
+		Tags:              append(tagsToClient(m.Tags), system...),
+		TransportZonePath: m.TransportZonePath.ValueString(),
+	}
+	for _, vlan := range m.VlanIds {
+		segment.VlanIds = append(segment.VlanIds, vlan.ValueString())
+	}
+	for _, subnet := range m.Subnets {
+		s := client.SegmentSubnet{GatewayAddress: subnet.GatewayAddress.ValueString()}
+		for _, r := range subnet.DhcpRanges {
+			s.DhcpRanges = append(s.DhcpRanges, r.ValueString())
+		}
+		segment.Subnets = append(segment.Subnets, s)
+	}
+	return segment
+}
+
+// setSegment copies a segment read from NSX to the model, leaving out the
+// tags which aren't configured on the resource.
+func (m *segmentResourceModel) setSegment(segment *client.Segment) {
+	m.Id = types.StringValue(segment.Id)
+	m.DisplayName = stringValueOrNull(segment.DisplayName)
+	m.Description = stringValueOrNull(segment.Description)
+	m.TransportZonePath = stringValueOrNull(segment.TransportZonePath)
+	m.ConnectivityPath = stringValueOrNull(segment.ConnectivityPath)
+	m.AdminState = stringValueOrNull(segment.AdminState)
+	m.Tags = newTags(managedTags(segment.Tags, nil, m.Tags))
+	m.Path = stringValueOrNull(segment.Path)
+	m.Revision = types.Int64PointerValue(segment.Revision)
+
+	m.VlanIds = nil
+	for _, vlan := range segment.VlanIds {
+		m.VlanIds = append(m.VlanIds, types.StringValue(vlan))
+	}
+	m.Subnets = nil
+	for _, subnet := range segment.Subnets {
+		s := segmentSubnetModel{
+			GatewayAddress: types.StringValue(subnet.GatewayAddress),
+			Network:        stringValueOrNull(subnet.Network),
+		}
+		for _, r := range subnet.DhcpRanges {
+			s.DhcpRanges = append(s.DhcpRanges, types.StringValue(r))
+		}
+		m.Subnets = append(m.Subnets, s)
+	}
+}
+
+// readSegment fetches a segment from NSX with readObject.
+func readSegment(ctx context.Context, c *client.Client, segment_id string) (*client.Segment, map[string]any, diag.Diagnostics) {
+	return readObject[client.Segment](func() (*http.Response, error) {
+		return c.GetSegment(ctx, segment_id)
+	}, "Segment", segmentFieldPaths)
+}
+
+// Create a new resource.
+func (r *segmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	tflog.Debug(ctx, "Preparing to create segment resource")
+	var plan segmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := infraClient(r.client, plan.Context, "Segments")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	segment_id := plan.Id.ValueString()
+	rsp, err := c.PatchSegment(ctx, client.PatchSegmentRequest{
+		SegmentId: segment_id,
+		Segment:   plan.toClient(nil),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create Segment", err.Error())
+		return
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		addAPIError(&resp.Diagnostics, "Unable to Create Segment", client.ParseAPIError(rsp), segmentFieldPaths)
+		return
+	}
+
+	created, _, diags := readSegment(ctx, c, segment_id)
+	resp.Diagnostics.Append(diags...)
+	if created == nil {
+		resp.Diagnostics.AddError("Unable to Create Segment", fmt.Sprintf("Segment %s was not found after it was created.", segment_id))
+		return
+	}
+	plan.setSegment(created)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	tflog.Debug(ctx, "Created segment resource", map[string]any{"success": true})
+}
+
+// Read resource information.
+func (r *segmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	tflog.Debug(ctx, "Preparing to read segment resource")
+	var state segmentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := infraClient(r.client, state.Context, "Segments")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	segment, _, diags := readSegment(ctx, c, state.Id.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Treat HTTP 404 Not Found status as a signal to remove/recreate resource
+	if segment == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	state.setSegment(segment)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Debug(ctx, "Finished reading segment resource", map[string]any{"success": true})
+}
+
+func (r *segmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	tflog.Debug(ctx, "Preparing to update segment resource")
+	var plan, state segmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := infraClient(r.client, plan.Context, "Segments")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	segment_id := plan.Id.ValueString()
+	current, currentObject, diags := readSegment(ctx, c, segment_id)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var system []client.Tag
+	var revision *int64
+	if current != nil {
+		system = systemTags(current.Tags)
+		revision = current.Revision
+	}
+	segment := plan.toClient(system)
+	segment.Revision = updateRevision(state.Revision, revision, r.lastWriterWins)
+
+	rsp, err := c.UpdateSegment(ctx, client.UpdateSegmentRequest{SegmentId: segment_id, Segment: segment, Current: currentObject})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Update Segment", err.Error())
+		return
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode == http.StatusPreconditionFailed {
+		// Refresh state from NSX, so that the next plan shows what changed.
+		current, _, diags := readSegment(ctx, c, segment_id)
+		resp.Diagnostics.Append(diags...)
+		if current == nil {
+			addRevisionConflict(&resp.Diagnostics, "Segment", segment_id, state.Revision.ValueInt64(), nil, "")
+			return
+		}
+		addRevisionConflict(&resp.Diagnostics, "Segment", segment_id, state.Revision.ValueInt64(), current.Revision, current.LastModifiedUser)
+		state.setSegment(current)
+		resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+		return
+	}
+	if rsp.StatusCode != http.StatusOK {
+		addAPIError(&resp.Diagnostics, "Unable to Update Segment", client.ParseAPIError(rsp), segmentFieldPaths)
+		return
+	}
+
+	updated, _, diags := readSegment(ctx, c, segment_id)
+	resp.Diagnostics.Append(diags...)
+	if updated == nil {
+		resp.Diagnostics.AddError("Unable to Update Segment", fmt.Sprintf("Segment %s was not found after it was updated.", segment_id))
+		return
+	}
+	plan.setSegment(updated)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	tflog.Debug(ctx, "Updated segment resource", map[string]any{"success": true})
+}
+
+func (r *segmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	tflog.Debug(ctx, "Preparing to delete segment resource")
+	var state segmentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := infraClient(r.client, state.Context, "Segments")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rsp, err := c.DeleteSegment(ctx, state.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Delete Segment", err.Error())
+		return
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK && rsp.StatusCode != http.StatusNotFound {
+		addAPIError(&resp.Diagnostics, "Unable to Delete Segment", client.ParseAPIError(rsp), nil)
+		return
+	}
+	tflog.Debug(ctx, "Deleted segment resource", map[string]any{"success": true})
+}
+
+func (r *segmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Segments in another project are imported by their policy path.
+	if strings.HasPrefix(req.ID, "/") {
+		pc, segment_id, err := client.ParseSegmentPath(req.ID)
+		if err != nil {
+			resp.Diagnostics.AddError("Unexpected Import Identifier", err.Error())
+			return
+		}
+		resp.Diagnostics.Append(importContext(ctx, &resp.State, r.client, pc, req.ID)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), segment_id)...)
+		return
+	}
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -5,9 +5,12 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
@@ -15,6 +18,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -30,13 +34,22 @@ func NewSegmentPortResource() resource.Resource {
 }
 
 type segmentPortResource struct {
-	client *client.Client
+	client             *client.Client
+	lastWriterWins     bool
+	realizationTimeout time.Duration
+	defaultTags        []client.Tag
 }
 
 type segmentPortResourceModel struct {
-	SegmentId   types.String       `tfsdk:"segment_id"`
-	PortId      types.String       `tfsdk:"port_id"`
-	SegmentPort client.SegmentPort `tfsdk:"segment_port"`
+	SegmentId   types.String `tfsdk:"segment_id"`
+	PortId      types.String `tfsdk:"port_id"`
+	Revision    types.Int64  `tfsdk:"revision"`
+	SegmentPort SegmentPort  `tfsdk:"segment_port"`
+
+	ForceDelete     types.Bool  `tfsdk:"force_delete"`
+	DeletionTimeout types.Int64 `tfsdk:"deletion_timeout"`
+
+	Context *policyContextModel `tfsdk:"context"`
 }
 
 func (r *segmentPortResource) Configure(ctx context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
@@ -44,12 +57,15 @@ func (r *segmentPortResource) Configure(ctx context.Context, req resource.Config
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.Client)
+	data, ok := req.ProviderData.(*NsxtIntervlanRoutingProviderData)
 	if !ok {
 		tflog.Error(ctx, "Unable to prepare client")
 		return
 	}
-	r.client = client
+	r.client = data.Client
+	r.lastWriterWins = data.LastWriterWins
+	r.realizationTimeout = data.RealizationTimeout
+	r.defaultTags = data.DefaultTags
 }
 
 // Metadata returns the resource type name.
@@ -77,95 +93,225 @@ func (r *segmentPortResource) Schema(_ context.Context, _ resource.SchemaRequest
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"revision": schema.Int64Attribute{
+				Description:         "NSX revision of the segment port when it was last read. Updates are rejected if the port has since been modified outside of Terraform.",
+				MarkdownDescription: "NSX revision of the segment port when it was last read. Updates are rejected if the port has since been modified outside of Terraform.",
+				Computed:            true,
+			},
 			"segment_port": schema.SingleNestedAttribute{
 				Description:         "The segment port definition.",
 				MarkdownDescription: "The segment port definition",
 				Required:            true,
+				Attributes:          segmentPortResourceAttributes(),
+			},
+			"force_delete": schema.BoolAttribute{
+				Description:         "Detach the port's attachment before deleting it, so that it can be deleted while a VIF is still connected. Defaults to false.",
+				MarkdownDescription: "Detach the port's attachment before deleting it, so that it can be deleted while a VIF is still connected. Defaults to `false`.",
+				Optional:            true,
+			},
+			"deletion_timeout": schema.Int64Attribute{
+				Description:         "Time in seconds to wait for the port to disappear from NSX after deleting it. Defaults to 0, which doesn't wait.",
+				MarkdownDescription: "Time in seconds to wait for the port to disappear from NSX after deleting it. Defaults to `0`, which doesn't wait.",
+				Optional:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"context": resourcePolicyContextBlock(),
+		},
+	}
+}
+
+// segmentPortResourceAttributes describes a segment port managed by a
+// resource.
+func segmentPortResourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"address_bindings": schema.ListNestedAttribute{
+			Description:         "List of IP address bindings. Only required when creating a CHILD port.",
+			MarkdownDescription: "List of IP address bindings. Only required when creating a CHILD port.",
+			Optional:            true,
+			NestedObject: schema.NestedAttributeObject{
 				Attributes: map[string]schema.Attribute{
-					"address_bindings": schema.ListNestedAttribute{
-						Description:         "List of IP address bindings. Only required when creating a CHILD port.",
-						MarkdownDescription: "List of IP address bindings. Only required when creating a CHILD port.",
-						Optional:            true,
-						NestedObject: schema.NestedAttributeObject{
-							Attributes: map[string]schema.Attribute{
-								"ip_address": schema.StringAttribute{
-									Description:         "IP address of segment port",
-									MarkdownDescription: "IP address of segment port",
-									Required:            true,
-								},
-								"mac_address": schema.StringAttribute{
-									Description:         "MAC address of segment port",
-									MarkdownDescription: "MAC address of segment port",
-									Required:            true,
-								},
-								"vlan_id": schema.StringAttribute{
-									Description:         "VLAN ID associated with this segment port",
-									MarkdownDescription: "VLAN ID associated with this segment port",
-									Required:            true,
-								},
-							},
-						},
+					"ip_address": schema.StringAttribute{
+						Description:         "IP address of segment port",
+						MarkdownDescription: "IP address of segment port",
+						Required:            true,
 					},
-					"admin_state": schema.StringAttribute{
-						Description:         "Admin state of the segment port. Can only be UP or DOWN values.",
-						MarkdownDescription: "Admin state of the segment port. Can only be UP or DOWN values.",
+					"mac_address": schema.StringAttribute{
+						Description:         "MAC address of segment port",
+						MarkdownDescription: "MAC address of segment port",
 						Required:            true,
 					},
-					"attachment": schema.SingleNestedAttribute{
-						Description:         "Attachment object definition",
-						MarkdownDescription: "Attachment object definition",
+					"vlan_id": schema.StringAttribute{
+						Description:         "VLAN ID associated with this segment port",
+						MarkdownDescription: "VLAN ID associated with this segment port",
 						Required:            true,
-						Attributes: map[string]schema.Attribute{
-							"id": schema.StringAttribute{
-								Description:         "VIF UUID in NSX. Required if type is PARENT.",
-								MarkdownDescription: "VIF UUID in NSX. Required if type is PARENT.",
-								Required:            true,
-							},
-							"context_id": schema.StringAttribute{
-								Description:         "Attachment UUID of the PARENT port. Only required when type is CHILD.",
-								MarkdownDescription: "Attachment UUID of the PARENT port. Only required when type is CHILD.",
-								Optional:            true,
-							},
-							"traffic_tag": schema.StringAttribute{
-								Description:         "VLAN ID to tag traffic with. Only required when type is CHILD.",
-								MarkdownDescription: "VLAN ID to tag traffic with. Only required when type is CHILD.",
-								Optional:            true,
-							},
-							"app_id": schema.StringAttribute{
-								Description:         "Application ID associated with this port. Can be the same as the display name. Only required when type is CHILD.",
-								MarkdownDescription: "Application ID associated with this port. Can be the same as the display name. Only required when type is CHILD.",
-								Optional:            true,
-							},
-							"type": schema.StringAttribute{
-								Description:         "Type of attachment. Case sensitive. Can be either PARENT or CHILD.",
-								MarkdownDescription: "Type of attachment. Case sensitive. Can be either PARENT or CHILD.",
-								Required:            true,
-							},
-						},
+						Validators:          []validator.String{vlanIdValidator()},
+					},
+				},
+			},
+		},
+		"ignored_address_bindings": schema.ListNestedAttribute{
+			Description:         "IP address bindings which NSX must not bind to the port, even if it discovers them.",
+			MarkdownDescription: "IP address bindings which NSX must not bind to the port, even if it discovers them.",
+			Optional:            true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"ip_address": schema.StringAttribute{
+						Description:         "IP address to ignore",
+						MarkdownDescription: "IP address to ignore",
+						Optional:            true,
 					},
-					"description": schema.StringAttribute{
-						Description:         "Description of segment port",
-						MarkdownDescription: "Description of segment port",
+					"mac_address": schema.StringAttribute{
+						Description:         "MAC address to ignore",
+						MarkdownDescription: "MAC address to ignore",
 						Optional:            true,
 					},
-					"display_name": schema.StringAttribute{
-						Description:         "Display name of segment port",
-						MarkdownDescription: "Display name of segment port",
+					"vlan_id": schema.StringAttribute{
+						Description:         "VLAN ID of the ignored binding",
+						MarkdownDescription: "VLAN ID of the ignored binding",
+						Optional:            true,
+						Validators:          []validator.String{vlanIdValidator()},
+					},
+				},
+			},
+		},
+		"init_state": schema.StringAttribute{
+			Description:         "Initial state of the port when it is created. Either UNBLOCKED_VLAN, to forward traffic before the port is realized, or RESTORE_VIF, to restore a VIF port.",
+			MarkdownDescription: "Initial state of the port when it is created. Either `UNBLOCKED_VLAN`, to forward traffic before the port is realized, or `RESTORE_VIF`, to restore a VIF port.",
+			Optional:            true,
+		},
+		"extra_configs": schema.ListNestedAttribute{
+			Description:         "Vendor specific configuration passed through to the port's hypervisor.",
+			MarkdownDescription: "Vendor specific configuration passed through to the port's hypervisor.",
+			Optional:            true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"key": schema.StringAttribute{
+						Description:         "Configuration key",
+						MarkdownDescription: "Configuration key",
 						Required:            true,
 					},
-					"id": schema.StringAttribute{
-						Description:         "Id of segment port. Can be the same as display_name.",
-						MarkdownDescription: "Id of segment port. Can be the same as display_name.",
+					"value": schema.StringAttribute{
+						Description:         "Configuration value",
+						MarkdownDescription: "Configuration value",
 						Required:            true,
 					},
-					"resource_type": schema.StringAttribute{
-						Description:         "Resource type of segment port. MUST be set to 'SegmentPort'",
-						MarkdownDescription: "Resource type of segment port. Can only be set to 'SegmentPort'",
+				},
+			},
+		},
+		"tags": schema.SetNestedAttribute{
+			Description:         "NSX tags of the segment port. Tags with a scope starting with nsx-, nsx/ or ncp/ are managed by NSX and its integrations, and are ignored.",
+			MarkdownDescription: "NSX tags of the segment port. Tags with a scope starting with `nsx-`, `nsx/` or `ncp/` are managed by NSX and its integrations, and are ignored.",
+			Optional:            true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"scope": schema.StringAttribute{
+						Description:         "Scope of the tag",
+						MarkdownDescription: "Scope of the tag",
+						Optional:            true,
+					},
+					"tag": schema.StringAttribute{
+						Description:         "Value of the tag",
+						MarkdownDescription: "Value of the tag",
 						Required:            true,
 					},
 				},
 			},
 		},
+		"path": schema.StringAttribute{
+			Description:         "Policy path of the segment port.",
+			MarkdownDescription: "Policy path of the segment port.",
+			Computed:            true,
+		},
+		"parent_path": schema.StringAttribute{
+			Description:         "Policy path of the segment the port belongs to.",
+			MarkdownDescription: "Policy path of the segment the port belongs to.",
+			Computed:            true,
+		},
+		"unique_id": schema.StringAttribute{
+			Description:         "NSX unique identifier of the segment port.",
+			MarkdownDescription: "NSX unique identifier of the segment port.",
+			Computed:            true,
+		},
+		"realization_id": schema.StringAttribute{
+			Description:         "Identifier of the logical port NSX realized the segment port as.",
+			MarkdownDescription: "Identifier of the logical port NSX realized the segment port as.",
+			Computed:            true,
+		},
+		"admin_state": schema.StringAttribute{
+			Description:         "Admin state of the segment port. Can only be UP or DOWN values.",
+			MarkdownDescription: "Admin state of the segment port. Can only be UP or DOWN values.",
+			Required:            true,
+		},
+		"attachment": schema.SingleNestedAttribute{
+			Description:         "Attachment object definition",
+			MarkdownDescription: "Attachment object definition",
+			Required:            true,
+			Attributes: map[string]schema.Attribute{
+				"id": schema.StringAttribute{
+					Description:         "VIF UUID in NSX. Required if type is PARENT.",
+					MarkdownDescription: "VIF UUID in NSX. Required if type is PARENT.",
+					Required:            true,
+				},
+				"context_id": schema.StringAttribute{
+					Description:         "Attachment UUID of the PARENT port. Only required when type is CHILD.",
+					MarkdownDescription: "Attachment UUID of the PARENT port. Only required when type is CHILD.",
+					Optional:            true,
+				},
+				"traffic_tag": schema.StringAttribute{
+					Description:         "VLAN ID to tag traffic with. Only required when type is CHILD.",
+					MarkdownDescription: "VLAN ID to tag traffic with. Only required when type is CHILD.",
+					Optional:            true,
+					Validators:          []validator.String{vlanIdValidator()},
+				},
+				"context_type": schema.StringAttribute{
+					Description:         "Type of the parent of a CHILD attachment. Either PARENTVIF or RESTORE_VIF.",
+					MarkdownDescription: "Type of the parent of a CHILD attachment. Either `PARENTVIF` or `RESTORE_VIF`.",
+					Optional:            true,
+				},
+				"evpn_vlans": schema.ListAttribute{
+					Description:         "VLAN ranges of an EVPN tenant, such as 100-200, when type is CHILD.",
+					MarkdownDescription: "VLAN ranges of an EVPN tenant, such as `100-200`, when type is CHILD.",
+					Optional:            true,
+					ElementType:         types.StringType,
+				},
+				"hyperbus_mode": schema.StringAttribute{
+					Description:         "Whether the attachment uses hyperbus for container traffic. Either ENABLE or DISABLE.",
+					MarkdownDescription: "Whether the attachment uses hyperbus for container traffic. Either `ENABLE` or `DISABLE`.",
+					Optional:            true,
+				},
+				"app_id": schema.StringAttribute{
+					Description:         "Application ID associated with this port. Can be the same as the display name. Only required when type is CHILD.",
+					MarkdownDescription: "Application ID associated with this port. Can be the same as the display name. Only required when type is CHILD.",
+					Optional:            true,
+				},
+				"type": schema.StringAttribute{
+					Description:         "Type of attachment. Case sensitive. Can be either PARENT or CHILD.",
+					MarkdownDescription: "Type of attachment. Case sensitive. Can be either PARENT or CHILD.",
+					Required:            true,
+				},
+			},
+		},
+		"description": schema.StringAttribute{
+			Description:         "Description of segment port",
+			MarkdownDescription: "Description of segment port",
+			Optional:            true,
+		},
+		"display_name": schema.StringAttribute{
+			Description:         "Display name of segment port",
+			MarkdownDescription: "Display name of segment port",
+			Required:            true,
+		},
+		"id": schema.StringAttribute{
+			Description:         "Id of segment port. Can be the same as display_name.",
+			MarkdownDescription: "Id of segment port. Can be the same as display_name.",
+			Required:            true,
+		},
+		"resource_type": schema.StringAttribute{
+			Description:         "Resource type of segment port. MUST be set to 'SegmentPort'",
+			MarkdownDescription: "Resource type of segment port. Can only be set to 'SegmentPort'",
+			Required:            true,
+		},
 	}
 }
 
@@ -180,9 +326,16 @@ func (r *segmentPortResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	c, diags := scopedClient(r.client, plan.Context)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	segment_id := plan.SegmentId.ValueString()
 	port_id := plan.PortId.ValueString()
-	segment_port := plan.SegmentPort
+	segment_port := plan.SegmentPort.ToClient()
+	segment_port.Tags = withDefaultTags(segment_port.Tags, r.defaultTags, nil)
 
 	patchRequest := client.PatchSegmentPortRequest{
 		SegmentId:   segment_id,
@@ -191,7 +344,7 @@ func (r *segmentPortResource) Create(ctx context.Context, req resource.CreateReq
 	}
 
 	// Create new item
-	spResponse, err := r.client.PatchSegmentPort(ctx, patchRequest)
+	spResponse, err := c.PatchSegmentPort(ctx, patchRequest)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Segment Port",
@@ -199,64 +352,70 @@ func (r *segmentPortResource) Create(ctx context.Context, req resource.CreateReq
 		)
 		return
 	}
+	defer spResponse.Body.Close()
 
-	if spResponse.StatusCode != 200 {
-		resp.Diagnostics.AddError(
-			"An invalid response was received. Code: "+string(spResponse.StatusCode),
-			spResponse.Status,
-		)
+	if spResponse.StatusCode != http.StatusOK {
+		addAPIError(&resp.Diagnostics, "Unable to Create Segment Port", client.ParseAPIError(spResponse), segmentPortFieldPaths)
 		return
 	}
 
+	// PATCH doesn't return the port, so read it back for its revision
+	plan.Revision = types.Int64Null()
+	created, _, diags := readSegmentPort(ctx, c, segment_id, port_id)
+	resp.Diagnostics.Append(diags...)
+	if created != nil {
+		plan.Revision = types.Int64PointerValue(created.Revision)
+	}
+	plan.SegmentPort.setComputed(created)
+
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	// The port is tracked in state even if it isn't realized, so that it is
+	// tainted rather than orphaned.
+	resp.Diagnostics.Append(waitForRealization(ctx, c, r.realizationTimeout, c.PolicyContext.SegmentPortPath(segment_id, port_id))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 	tflog.Debug(ctx, "Created segment port resource", map[string]any{"success": true})
 }
 
 // Read resource information.
 func (r *segmentPortResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	tflog.Debug(ctx, "Preparing to read item resource")
-	// Get current state
+	// Get current state. Only the identifiers are read, as the rest of the
+	// state is still null after an import.
 	var state segmentPortResourceModel
-	diags := req.State.Get(ctx, &state)
-	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("segment_id"), &state.SegmentId)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("port_id"), &state.PortId)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("context"), &state.Context)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("force_delete"), &state.ForceDelete)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("deletion_timeout"), &state.DeletionTimeout)...)
+	var prior []Tag
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("segment_port").AtName("tags"), &prior)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	spResponse, err := r.client.GetSegmentPort(ctx, state.SegmentId.ValueString(), state.PortId.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Read Segment Port configuration",
-			err.Error(),
-		)
-		return
-	}
-
-	// Treat HTTP 404 Not Found status as a signal to remove/recreate resource
-	if spResponse.StatusCode == http.StatusNotFound {
-		resp.State.RemoveResource(ctx)
+	c, diags := scopedClient(r.client, state.Context)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if spResponse.StatusCode != http.StatusOK {
-		resp.Diagnostics.AddError(
-			"Unexpected HTTP error code received for segment port",
-			spResponse.Status,
-		)
+	newSegmentPort, _, diags := readSegmentPort(ctx, c, state.SegmentId.ValueString(), state.PortId.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	var newSegmentPort client.SegmentPort
-	if err := json.NewDecoder(spResponse.Body).Decode(&newSegmentPort); err != nil {
-		resp.Diagnostics.AddError(
-			"Invalid format received for Item",
-			err.Error(),
-		)
+	// Treat HTTP 404 Not Found status as a signal to remove/recreate resource
+	if newSegmentPort == nil {
+		resp.State.RemoveResource(ctx)
 		return
 	}
 
@@ -264,7 +423,13 @@ func (r *segmentPortResource) Read(ctx context.Context, req resource.ReadRequest
 	state = segmentPortResourceModel{
 		SegmentId:   state.SegmentId,
 		PortId:      state.PortId,
-		SegmentPort: newSegmentPort,
+		Revision:    types.Int64PointerValue(newSegmentPort.Revision),
+		SegmentPort: stateSegmentPort(*newSegmentPort, r.defaultTags, prior),
+
+		ForceDelete:     state.ForceDelete,
+		DeletionTimeout: state.DeletionTimeout,
+
+		Context: state.Context,
 	}
 
 	// Set refreshed state
@@ -279,50 +444,111 @@ func (r *segmentPortResource) Read(ctx context.Context, req resource.ReadRequest
 func (r *segmentPortResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	tflog.Debug(ctx, "Preparing to update segment port resource")
 	// Retrieve values from plan
-	var plan segmentPortResourceModel
+	var plan, state segmentPortResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := scopedClient(r.client, plan.Context)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	segment_id := plan.SegmentId.ValueString()
 	port_id := plan.PortId.ValueString()
-	segment_port := plan.SegmentPort
+	current, currentObject, diags := readSegmentPort(ctx, c, segment_id, port_id)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var system []client.Tag
+	var revision *int64
+	if current != nil {
+		system = systemTags(current.Tags)
+		revision = current.Revision
+	}
+	segment_port := plan.SegmentPort.ToClient()
+	segment_port.Tags = withDefaultTags(segment_port.Tags, r.defaultTags, system)
+	segment_port.Revision = updateRevision(state.Revision, revision, r.lastWriterWins)
 
-	patchRequest := client.PatchSegmentPortRequest{
+	// Update existing item
+	spResponse, err := c.UpdateSegmentPort(ctx, client.UpdateSegmentPortRequest{
 		SegmentId:   segment_id,
 		PortId:      port_id,
 		SegmentPort: segment_port,
-	}
-
-	// Create new item
-	spResponse, err := r.client.PatchSegmentPort(ctx, patchRequest)
+		Current:     currentObject,
+	})
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to Create Segment Port",
+			"Unable to Update Segment Port",
 			err.Error(),
 		)
 		return
 	}
+	defer spResponse.Body.Close()
+
+	if spResponse.StatusCode == http.StatusPreconditionFailed {
+		// Refresh state from NSX, so that the next plan shows what changed.
+		current, _, diags := readSegmentPort(ctx, c, segment_id, port_id)
+		resp.Diagnostics.Append(diags...)
+		id := port_id + " on segment " + segment_id
+		if current == nil {
+			addRevisionConflict(&resp.Diagnostics, "Segment Port", id, state.Revision.ValueInt64(), nil, "")
+			return
+		}
+		addRevisionConflict(&resp.Diagnostics, "Segment Port", id, state.Revision.ValueInt64(), current.Revision, current.LastModifiedUser)
+		state.Revision = types.Int64PointerValue(current.Revision)
+		state.SegmentPort = stateSegmentPort(*current, r.defaultTags, state.SegmentPort.Tags)
+		resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+		return
+	}
 
-	if spResponse.StatusCode != 200 {
-		resp.Diagnostics.AddError(
-			"An invalid response was received. Code: "+string(spResponse.StatusCode),
-			spResponse.Status,
-		)
+	if spResponse.StatusCode != http.StatusOK {
+		addAPIError(&resp.Diagnostics, "Unable to Update Segment Port", client.ParseAPIError(spResponse), segmentPortFieldPaths)
 		return
 	}
 
+	plan.Revision = types.Int64Null()
+	updated, _, diags := readSegmentPort(ctx, c, segment_id, port_id)
+	resp.Diagnostics.Append(diags...)
+	if updated != nil {
+		plan.Revision = types.Int64PointerValue(updated.Revision)
+	}
+	plan.SegmentPort.setComputed(updated)
+
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.Diagnostics.Append(waitForRealization(ctx, c, r.realizationTimeout, c.PolicyContext.SegmentPortPath(segment_id, port_id))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 	tflog.Debug(ctx, "Updated segment port resource", map[string]any{"success": true})
 }
 
+// stateSegmentPort converts a port read from NSX to its state, leaving out
+// the tags which aren't configured on the resource.
+func stateSegmentPort(sp client.SegmentPort, defaultTags []client.Tag, prior []Tag) SegmentPort {
+	sp.Tags = managedTags(sp.Tags, defaultTags, prior)
+	return NewSegmentPort(sp)
+}
+
+// readSegmentPort fetches a segment port from NSX with readObject.
+func readSegmentPort(ctx context.Context, c *client.Client, segment_id string, port_id string) (*client.SegmentPort, map[string]any, diag.Diagnostics) {
+	return readObject[client.SegmentPort](func() (*http.Response, error) {
+		return c.GetSegmentPort(ctx, segment_id, port_id)
+	}, "Segment Port", segmentPortFieldPaths)
+}
+
 func (r *segmentPortResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	tflog.Debug(ctx, "Preparing to delete segment port resource")
 	// Retrieve values from state
@@ -333,32 +559,87 @@ func (r *segmentPortResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
+	c, diags := scopedClient(r.client, state.Context)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	segment_id := state.SegmentId.ValueString()
+	port_id := state.PortId.ValueString()
+
+	if state.ForceDelete.ValueBool() {
+		if err := c.DetachSegmentPort(ctx, segment_id, port_id); err != nil {
+			addAPIError(&resp.Diagnostics, "Unable to Detach Segment Port", err, nil)
+			return
+		}
+	}
+
 	// delete item
-	_, err := r.client.DeleteSegmentPort(ctx, state.SegmentId.ValueString(), state.PortId.ValueString())
+	spResponse, err := c.DeleteSegmentPort(ctx, segment_id, port_id)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to Delete Item",
+			"Unable to Delete Segment Port",
 			err.Error(),
 		)
 		return
 	}
+	defer spResponse.Body.Close()
+
+	switch spResponse.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		tflog.Debug(ctx, "Segment port was already deleted", map[string]any{"segment_id": segment_id, "port_id": port_id})
+		return
+	default:
+		apiErr := client.ParseAPIError(spResponse)
+		detail := fmt.Sprintf("NSX refused to delete segment port %s on segment %s. %s", port_id, segment_id, apiErr.Error())
+		for _, related := range apiErr.RelatedErrors {
+			detail += "\n  - " + related.ErrorMessage
+		}
+		if !state.ForceDelete.ValueBool() {
+			detail += "\n\nIf a VIF is still connected to the port, disconnect it, or set force_delete to detach it before the port is deleted."
+		}
+		resp.Diagnostics.AddError("Segment Port Not Deleted", detail)
+		return
+	}
+
+	if timeout := state.DeletionTimeout.ValueInt64(); timeout > 0 {
+		waitCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		defer cancel()
+		if err := c.WaitForSegmentPortDeletion(waitCtx, segment_id, port_id, pollInterval); err != nil {
+			addAPIError(&resp.Diagnostics, "Segment Port Not Deleted", err, nil)
+			return
+		}
+	}
 	tflog.Debug(ctx, "Deleted segment port resource", map[string]any{"success": true})
 }
 
 func (r *segmentPortResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	// If our ID was a string then we could do this
-	resource.ImportStatePassthroughID(ctx, path.Root("port_id"), req, resp)
-
-	//id, err := strconv.ParseInt(req.ID, 10, 64)
-	//
-	//if err != nil {
-	//	resp.Diagnostics.AddError(
-	//		"Error importing item",
-	//		"Could not import item, unexpected error (ID should be an integer): "+err.Error(),
-	//	)
-	//	return
-	//}
-
-	//resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	// Ports in another project or VPC are imported by their policy path.
+	if strings.HasPrefix(req.ID, "/") {
+		pc, segment_id, port_id, err := client.ParseSegmentPortPath(req.ID)
+		if err != nil {
+			resp.Diagnostics.AddError("Unexpected Import Identifier", err.Error())
+			return
+		}
+		resp.Diagnostics.Append(importContext(ctx, &resp.State, r.client, pc, req.ID)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("segment_id"), segment_id)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("port_id"), port_id)...)
+		return
+	}
+
+	// A port is only unique within its segment, so import IDs have the form
+	// <segment_id>/<port_id>.
+	segment_id, port_id, ok := strings.Cut(req.ID, "/")
+	if !ok || segment_id == "" || port_id == "" || strings.Contains(port_id, "/") {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: segment_id/port_id or a segment port policy path. Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("segment_id"), segment_id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("port_id"), port_id)...)
 }
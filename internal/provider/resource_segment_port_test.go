@@ -0,0 +1,698 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/internal/nsxtest"
+)
+
+const (
+	testAccParentPort = "nsxt-intervlan-routing_segment_port.parent"
+	testAccChildPort  = "nsxt-intervlan-routing_segment_port.child"
+)
+
+func testAccParentPortConfig(srv *nsxtest.Server, displayName string) string {
+	return testAccProviderConfig(srv) + testAccParentPortResource(displayName)
+}
+
+func testAccParentPortResource(displayName string) string {
+	return fmt.Sprintf(`
+resource "nsxt-intervlan-routing_segment_port" "parent" {
+  segment_id = "parent-segment"
+  port_id    = "parent-port"
+  segment_port = {
+    admin_state = "UP"
+    attachment = {
+      id   = "9765bf41-9725-4714-977e-7f7395920de2"
+      type = "PARENT"
+    }
+    description   = "Parent port"
+    display_name  = %q
+    id            = "parent-port"
+    resource_type = "SegmentPort"
+  }
+}
+`, displayName)
+}
+
+func testAccChildPortConfig(srv *nsxtest.Server, vlan string) string {
+	return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "nsxt-intervlan-routing_segment_port" "child" {
+  segment_id = "child-segment"
+  port_id    = "child-port"
+  segment_port = {
+    address_bindings = [
+      {
+        ip_address  = "169.254.254.169"
+        mac_address = "00:50:56:ad:5e:64"
+        vlan_id     = %[1]q
+      },
+    ]
+    admin_state = "UP"
+    attachment = {
+      id          = "a274ac51-88f5-491f-a46f-840d409ce82f"
+      context_id  = "9765bf41-9725-4714-977e-7f7395920de2"
+      traffic_tag = %[1]q
+      app_id      = "Segment%[1]s"
+      type        = "CHILD"
+    }
+    display_name  = "Child port %[1]s"
+    id            = "child-port"
+    resource_type = "SegmentPort"
+  }
+}
+`, vlan)
+}
+
+func TestAccSegmentPortResourceParent(t *testing.T) {
+	srv := testAccNewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccParentPortConfig(srv, "parent-one"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("segment_port").AtMapKey("display_name"), knownvalue.StringExact("parent-one")),
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("segment_port").AtMapKey("attachment").AtMapKey("type"), knownvalue.StringExact("PARENT")),
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("revision"), knownvalue.Int64Exact(0)),
+				},
+			},
+			// ImportState testing
+			{
+				ResourceName:      testAccParentPort,
+				ImportState:       true,
+				ImportStateId:     "parent-segment/parent-port",
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccParentPortConfig(srv, "parent-two"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(testAccParentPort, plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("segment_port").AtMapKey("display_name"), knownvalue.StringExact("parent-two")),
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("revision"), knownvalue.Int64Exact(1)),
+				},
+			},
+			// Fields the resource doesn't model are kept when updating the
+			// port.
+			{
+				PreConfig: func() {
+					srv.SetObjectField("/infra/segments/parent-segment/ports/parent-port", "origin_id", "vm-42")
+				},
+				Config: testAccParentPortConfig(srv, "parent-three"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("segment_port").AtMapKey("display_name"), knownvalue.StringExact("parent-three")),
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("revision"), knownvalue.Int64Exact(3)),
+				},
+				Check: testAccCheckObjectField(srv, "/infra/segments/parent-segment/ports/parent-port", "origin_id", "vm-42"),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+		CheckDestroy: func(_ *terraform.State) error {
+			if _, ok := srv.SegmentPort("parent-segment", "parent-port"); ok {
+				return fmt.Errorf("segment port parent-port still exists")
+			}
+			return nil
+		},
+	})
+}
+
+func TestAccSegmentPortResourceChild(t *testing.T) {
+	srv := testAccNewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccChildPortConfig(srv, "1001"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccChildPort, tfjsonpath.New("segment_port").AtMapKey("attachment").AtMapKey("context_id"), knownvalue.StringExact("9765bf41-9725-4714-977e-7f7395920de2")),
+					statecheck.ExpectKnownValue(testAccChildPort, tfjsonpath.New("segment_port").AtMapKey("address_bindings"), knownvalue.ListSizeExact(1)),
+				},
+			},
+			{
+				ResourceName:      testAccChildPort,
+				ImportState:       true,
+				ImportStateId:     "child-segment/child-port",
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccChildPortConfig(srv, "1002"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccChildPort, tfjsonpath.New("segment_port").AtMapKey("attachment").AtMapKey("traffic_tag"), knownvalue.StringExact("1002")),
+					statecheck.ExpectKnownValue(testAccChildPort, tfjsonpath.New("segment_port").AtMapKey("address_bindings").AtSliceIndex(0).AtMapKey("vlan_id"), knownvalue.StringExact("1002")),
+				},
+			},
+		},
+	})
+}
+
+func TestAccSegmentPortResourceDrift(t *testing.T) {
+	srv := testAccNewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccParentPortConfig(srv, "parent"),
+			},
+			// A change made outside of Terraform is detected and reverted.
+			{
+				PreConfig: func() {
+					port, _ := srv.SegmentPort("parent-segment", "parent-port")
+					port.DisplayName = "changed-out-of-band"
+					srv.SetSegmentPort("parent-segment", port)
+				},
+				Config: testAccParentPortConfig(srv, "parent"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(testAccParentPort, plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("segment_port").AtMapKey("display_name"), knownvalue.StringExact("parent")),
+				},
+			},
+			// A port deleted outside of Terraform is removed from state and
+			// created again.
+			{
+				PreConfig: func() {
+					srv.DeleteSegmentPort("parent-segment", "parent-port")
+				},
+				Config: testAccParentPortConfig(srv, "parent"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(testAccParentPort, plancheck.ResourceActionCreate),
+					},
+				},
+			},
+		},
+	})
+}
+
+// testAccModifyBeforeApply is a plan check which runs a function once the
+// plan has been made, such as a change made outside of Terraform between
+// the refresh and the apply.
+type testAccModifyBeforeApply func()
+
+func (f testAccModifyBeforeApply) CheckPlan(context.Context, plancheck.CheckPlanRequest, *plancheck.CheckPlanResponse) {
+	f()
+}
+
+// testAccModifyParentPort changes the description of the parent port
+// outside of Terraform, which bumps its revision.
+func testAccModifyParentPort(srv *nsxtest.Server, description string) testAccModifyBeforeApply {
+	return func() {
+		port, _ := srv.SegmentPort("parent-segment", "parent-port")
+		port.Description = description
+		srv.SetSegmentPort("parent-segment", port)
+	}
+}
+
+func TestAccSegmentPortResourceRevisionConflict(t *testing.T) {
+	srv := testAccNewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccParentPortConfig(srv, "parent-one"),
+			},
+			// A change made between the refresh and the apply is reported
+			// rather than overwritten, and state is refreshed.
+			{
+				Config: testAccParentPortConfig(srv, "parent-two"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						testAccModifyParentPort(srv, "changed-out-of-band"),
+					},
+				},
+				ExpectError: regexp.MustCompile(`(?s)Segment Port Revision Conflict.*last modified by\s+other-user`),
+			},
+			{
+				ResourceName: testAccParentPort,
+				ImportState:  true,
+				ImportStateIdFunc: func(state *terraform.State) (string, error) {
+					attributes := state.RootModule().Resources[testAccParentPort].Primary.Attributes
+					if attributes["revision"] != "1" || attributes["segment_port.description"] != "changed-out-of-band" {
+						return "", fmt.Errorf("expected state to be refreshed after the conflict, got revision %s and description %q", attributes["revision"], attributes["segment_port.description"])
+					}
+					return "parent-segment/parent-port", nil
+				},
+			},
+			// With last_writer_wins, the change is overwritten.
+			{
+				Config: testAccLastWriterWinsConfig(srv) + testAccParentPortResource("parent-two"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						testAccModifyParentPort(srv, "changed-again"),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("segment_port").AtMapKey("display_name"), knownvalue.StringExact("parent-two")),
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("segment_port").AtMapKey("description"), knownvalue.StringExact("Parent port")),
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("revision"), knownvalue.Int64Exact(3)),
+				},
+			},
+		},
+	})
+}
+
+func TestAccSegmentPortResourceProject(t *testing.T) {
+	srv := testAccNewServer(t)
+	project := client.PolicyContext{ProjectId: "dev"}
+	srv.AddSegmentAt(project, "parent-segment")
+
+	config := testAccProviderConfig(srv) + `
+resource "nsxt-intervlan-routing_segment_port" "parent" {
+  segment_id = "parent-segment"
+  port_id    = "parent-port"
+  context {
+    project_id = "dev"
+  }
+  segment_port = {
+    admin_state = "UP"
+    attachment = {
+      id   = "9765bf41-9725-4714-977e-7f7395920de2"
+      type = "PARENT"
+    }
+    display_name  = "parent-port"
+    id            = "parent-port"
+    resource_type = "SegmentPort"
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: func(_ *terraform.State) error {
+					if _, ok := srv.SegmentPortAt(project, "parent-segment", "parent-port"); !ok {
+						return fmt.Errorf("segment port parent-port was not created in project dev")
+					}
+					if _, ok := srv.SegmentPort("parent-segment", "parent-port"); ok {
+						return fmt.Errorf("segment port parent-port was created in the default space")
+					}
+					return nil
+				},
+			},
+			// Ports outside of the provider context are imported by their
+			// policy path.
+			{
+				ResourceName:      testAccParentPort,
+				ImportState:       true,
+				ImportStateId:     "/orgs/default/projects/dev/infra/segments/parent-segment/ports/parent-port",
+				ImportStateVerify: true,
+			},
+		},
+		CheckDestroy: func(_ *terraform.State) error {
+			if _, ok := srv.SegmentPortAt(project, "parent-segment", "parent-port"); ok {
+				return fmt.Errorf("segment port parent-port still exists")
+			}
+			return nil
+		},
+	})
+}
+
+func TestAccSegmentPortResourceRealization(t *testing.T) {
+	srv := testAccNewServer(t)
+	const intentPath = "/infra/segments/parent-segment/ports/parent-port"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// A port which NSX fails to realize is reported with the
+			// realization error.
+			{
+				PreConfig: func() {
+					srv.SetRealizedState(intentPath, "ERROR", "VIF 9765bf41-9725-4714-977e-7f7395920de2 not found")
+				},
+				Config:      testAccParentPortConfig(srv, "parent"),
+				ExpectError: regexp.MustCompile(`VIF 9765bf41-9725-4714-977e-7f7395920de2 not found`),
+			},
+			// The port is tainted, so it is replaced once it can be realized.
+			{
+				PreConfig: func() {
+					srv.SetRealizedState(intentPath, "SUCCESS")
+				},
+				Config: testAccParentPortConfig(srv, "parent"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(testAccParentPort, plancheck.ResourceActionReplace),
+					},
+				},
+			},
+		},
+	})
+}
+
+func testAccForceDeletePortConfig(srv *nsxtest.Server, forceDelete bool) string {
+	return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "nsxt-intervlan-routing_segment_port" "parent" {
+  segment_id       = "parent-segment"
+  port_id          = "parent-port"
+  force_delete     = %t
+  deletion_timeout = 5
+  segment_port = {
+    admin_state = "UP"
+    attachment = {
+      id   = "9765bf41-9725-4714-977e-7f7395920de2"
+      type = "PARENT"
+    }
+    display_name  = "parent-port"
+    id            = "parent-port"
+    resource_type = "SegmentPort"
+  }
+}
+`, forceDelete)
+}
+
+func TestAccSegmentPortResourceForceDelete(t *testing.T) {
+	srv := testAccNewServer(t)
+	srv.RejectAttachedPortDeletes = true
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccForceDeletePortConfig(srv, false),
+			},
+			// NSX refuses to delete a port with a connected VIF, and the
+			// port stays in state rather than being orphaned.
+			{
+				Config:      testAccProviderConfig(srv),
+				ExpectError: regexp.MustCompile(`NSX refused to delete segment port parent-port`),
+			},
+			// With force_delete the attachment is removed first, so the
+			// port is deleted when the test is destroyed.
+			{
+				Config: testAccForceDeletePortConfig(srv, true),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("force_delete"), knownvalue.Bool(true)),
+				},
+			},
+		},
+		CheckDestroy: func(_ *terraform.State) error {
+			if _, ok := srv.SegmentPort("parent-segment", "parent-port"); ok {
+				return fmt.Errorf("segment port parent-port still exists")
+			}
+			return nil
+		},
+	})
+}
+
+func TestAccSegmentPortResourceGlobalManager(t *testing.T) {
+	srv := testAccNewServer(t)
+	global := client.PolicyContext{GlobalManager: true}
+	srv.AddSegmentAt(global, "parent-segment")
+	srv.AddSite("paris")
+
+	config := fmt.Sprintf(`
+provider "nsxt-intervlan-routing" {
+  host           = %q
+  username       = %q
+  password       = %q
+  global_manager = true
+}
+`, srv.URL, nsxtest.Username, nsxtest.Password) + testAccParentPortResource("parent") + `
+data "nsxt-intervlan-routing_segment_ports" "paris" {
+  segment_id = "parent-segment"
+  site       = "paris"
+
+  depends_on = [nsxt-intervlan-routing_segment_port.parent]
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: func(_ *terraform.State) error {
+					if _, ok := srv.SegmentPortAt(global, "parent-segment", "parent-port"); !ok {
+						return fmt.Errorf("segment port parent-port was not created in the global infra")
+					}
+					if _, ok := srv.SegmentPort("parent-segment", "parent-port"); ok {
+						return fmt.Errorf("segment port parent-port was created in the local infra")
+					}
+					return nil
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("data.nsxt-intervlan-routing_segment_ports.paris", tfjsonpath.New("segment_ports"), knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.ObjectPartial(map[string]knownvalue.Check{"id": knownvalue.StringExact("parent-port")}),
+					})),
+				},
+			},
+			{
+				ResourceName:      testAccParentPort,
+				ImportState:       true,
+				ImportStateId:     "/global-infra/segments/parent-segment/ports/parent-port",
+				ImportStateVerify: true,
+			},
+			{
+				Config:      strings.Replace(config, `site       = "paris"`, `site       = "london"`, 1),
+				ExpectError: regexp.MustCompile(`london`),
+			},
+		},
+	})
+}
+
+func TestAccSegmentPortResourceFullModel(t *testing.T) {
+	srv := testAccNewServer(t)
+
+	config := testAccProviderConfig(srv) + `
+resource "nsxt-intervlan-routing_segment_port" "parent" {
+  segment_id = "parent-segment"
+  port_id    = "parent-port"
+  segment_port = {
+    admin_state = "UP"
+    attachment = {
+      id            = "9765bf41-9725-4714-977e-7f7395920de2"
+      type          = "PARENT"
+      hyperbus_mode = "DISABLE"
+    }
+    display_name = "parent-port"
+    id           = "parent-port"
+    init_state   = "UNBLOCKED_VLAN"
+    extra_configs = [
+      {
+        key   = "vmx.option"
+        value = "true"
+      },
+    ]
+    ignored_address_bindings = [
+      {
+        ip_address = "169.254.0.1"
+        vlan_id    = "10"
+      },
+    ]
+    tags = [
+      {
+        scope = "owner"
+        tag   = "network-team"
+      },
+      {
+        tag = "production"
+      },
+    ]
+    resource_type = "SegmentPort"
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("segment_port").AtMapKey("path"), knownvalue.StringExact("/infra/segments/parent-segment/ports/parent-port")),
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("segment_port").AtMapKey("parent_path"), knownvalue.StringExact("/infra/segments/parent-segment")),
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("segment_port").AtMapKey("unique_id"), knownvalue.NotNull()),
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("segment_port").AtMapKey("realization_id"), knownvalue.NotNull()),
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("segment_port").AtMapKey("tags"), knownvalue.SetExact([]knownvalue.Check{
+						knownvalue.ObjectExact(map[string]knownvalue.Check{"scope": knownvalue.StringExact("owner"), "tag": knownvalue.StringExact("network-team")}),
+						knownvalue.ObjectExact(map[string]knownvalue.Check{"scope": knownvalue.Null(), "tag": knownvalue.StringExact("production")}),
+					})),
+				},
+				Check: func(_ *terraform.State) error {
+					port, ok := srv.SegmentPort("parent-segment", "parent-port")
+					if !ok {
+						return fmt.Errorf("segment port parent-port was not created")
+					}
+					if port.InitState != "UNBLOCKED_VLAN" || len(port.ExtraConfigs) != 1 || len(port.Tags) != 2 {
+						return fmt.Errorf("segment port parent-port was not created with the full model: %+v", port)
+					}
+					if len(port.IgnoredAddressBindings) != 1 || port.IgnoredAddressBindings[0].VlanId != 10 {
+						return fmt.Errorf("expected the ignored binding to be sent with a numeric VLAN ID: %+v", port.IgnoredAddressBindings)
+					}
+					return nil
+				},
+			},
+			{
+				ResourceName:      testAccParentPort,
+				ImportState:       true,
+				ImportStateId:     "parent-segment/parent-port",
+				ImportStateVerify: true,
+			},
+			{
+				Config:      strings.Replace(config, `vlan_id    = "10"`, `vlan_id    = "ten"`, 1),
+				ExpectError: regexp.MustCompile(`integer between 0 and 4094`),
+			},
+		},
+	})
+}
+
+func testAccTagsConfig(srv *nsxtest.Server, displayName string) string {
+	return fmt.Sprintf(`
+provider "nsxt-intervlan-routing" {
+  host     = %q
+  username = %q
+  password = %q
+
+  default_tags = [
+    {
+      scope = "cost-centre"
+      tag   = "1234"
+    },
+    {
+      scope = "owner"
+      tag   = "platform-team"
+    },
+    {
+      tag = "terraform"
+    },
+  ]
+}
+
+resource "nsxt-intervlan-routing_segment_port" "parent" {
+  segment_id = "parent-segment"
+  port_id    = "parent-port"
+  segment_port = {
+    admin_state = "UP"
+    attachment = {
+      id   = "9765bf41-9725-4714-977e-7f7395920de2"
+      type = "PARENT"
+    }
+    display_name = %q
+    id           = "parent-port"
+    tags = [
+      {
+        scope = "owner"
+        tag   = "network-team"
+      },
+      {
+        tag = "web"
+      },
+    ]
+    resource_type = "SegmentPort"
+  }
+}
+`, srv.URL, nsxtest.Username, nsxtest.Password, displayName)
+}
+
+// testAccCheckPortTags checks the tags of the parent port in srv.
+func testAccCheckPortTags(srv *nsxtest.Server, expected ...client.Tag) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		port, ok := srv.SegmentPort("parent-segment", "parent-port")
+		if !ok {
+			return fmt.Errorf("segment port parent-port does not exist")
+		}
+		actual := map[client.Tag]bool{}
+		for _, tag := range port.Tags {
+			actual[tag] = true
+		}
+		if len(actual) != len(expected) {
+			return fmt.Errorf("expected tags %v, got %v", expected, port.Tags)
+		}
+		for _, tag := range expected {
+			if !actual[tag] {
+				return fmt.Errorf("expected tags %v, got %v", expected, port.Tags)
+			}
+		}
+		return nil
+	}
+}
+
+func TestAccSegmentPortResourceTags(t *testing.T) {
+	srv := testAccNewServer(t)
+	ncp := client.Tag{Scope: "ncp/cluster", Tag: "k8s-prod"}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Default tags are added unless the port has a tag with the same
+			// scope, and are left out of state. A default tag without a
+			// scope is added even though the port has another tag without
+			// one.
+			{
+				Config: testAccTagsConfig(srv, "parent"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccParentPort, tfjsonpath.New("segment_port").AtMapKey("tags"), knownvalue.SetExact([]knownvalue.Check{
+						knownvalue.ObjectExact(map[string]knownvalue.Check{"scope": knownvalue.StringExact("owner"), "tag": knownvalue.StringExact("network-team")}),
+						knownvalue.ObjectExact(map[string]knownvalue.Check{"scope": knownvalue.Null(), "tag": knownvalue.StringExact("web")}),
+					})),
+				},
+				Check: testAccCheckPortTags(srv,
+					client.Tag{Scope: "owner", Tag: "network-team"},
+					client.Tag{Tag: "web"},
+					client.Tag{Scope: "cost-centre", Tag: "1234"},
+					client.Tag{Tag: "terraform"},
+				),
+			},
+			// A system tag added by NSX doesn't cause a diff, and is kept
+			// when the port is updated.
+			{
+				PreConfig: func() {
+					port, _ := srv.SegmentPort("parent-segment", "parent-port")
+					port.Tags = append(port.Tags, ncp)
+					srv.SetSegmentPort("parent-segment", port)
+				},
+				Config: testAccTagsConfig(srv, "parent"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				Config: testAccTagsConfig(srv, "renamed"),
+				Check: testAccCheckPortTags(srv,
+					client.Tag{Scope: "owner", Tag: "network-team"},
+					client.Tag{Tag: "web"},
+					client.Tag{Scope: "cost-centre", Tag: "1234"},
+					client.Tag{Tag: "terraform"},
+					ncp,
+				),
+			},
+			{
+				ResourceName:      testAccParentPort,
+				ImportState:       true,
+				ImportStateId:     "parent-segment/parent-port",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,376 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+var (
+	_ resource.Resource              = &segmentPortTrunkResource{}
+	_ resource.ResourceWithConfigure = &segmentPortTrunkResource{}
+)
+
+func NewSegmentPortTrunkResource() resource.Resource {
+	return &segmentPortTrunkResource{}
+}
+
+// segmentPortTrunkResource manages a PARENT port and its CHILD ports as a
+// unit. Every change is submitted as a single Hierarchical Policy API
+// transaction, so a trunk is never left half created.
+type segmentPortTrunkResource struct {
+	client             *client.Client
+	realizationTimeout time.Duration
+	defaultTags        []client.Tag
+}
+
+type segmentPortTrunkResourceModel struct {
+	Id       types.String             `tfsdk:"id"`
+	Parent   segmentPortTrunkMember   `tfsdk:"parent"`
+	Children []segmentPortTrunkMember `tfsdk:"children"`
+
+	Context *policyContextModel `tfsdk:"context"`
+}
+
+type segmentPortTrunkMember struct {
+	SegmentId   types.String `tfsdk:"segment_id"`
+	PortId      types.String `tfsdk:"port_id"`
+	SegmentPort SegmentPort  `tfsdk:"segment_port"`
+}
+
+func (m segmentPortTrunkMember) key() string {
+	return m.SegmentId.ValueString() + "/" + m.PortId.ValueString()
+}
+
+func (r *segmentPortTrunkResource) Configure(ctx context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*NsxtIntervlanRoutingProviderData)
+	if !ok {
+		tflog.Error(ctx, "Unable to prepare client")
+		return
+	}
+	r.client = data.Client
+	r.realizationTimeout = data.RealizationTimeout
+	r.defaultTags = data.DefaultTags
+}
+
+// Metadata returns the resource type name.
+func (r *segmentPortTrunkResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_segment_port_trunk"
+}
+
+func (r *segmentPortTrunkResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manage a PARENT segment port and its CHILD ports in a single transaction.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description:         "Identifier of the trunk, in the form <segment_id>/<port_id> of the parent port.",
+				MarkdownDescription: "Identifier of the trunk, in the form `<segment_id>/<port_id>` of the parent port.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"parent": schema.SingleNestedAttribute{
+				Description:         "The PARENT port of the trunk. Changing its segment or port ID replaces the whole trunk.",
+				MarkdownDescription: "The PARENT port of the trunk. Changing its segment or port ID replaces the whole trunk.",
+				Required:            true,
+				Attributes: segmentPortTrunkMemberAttributes(
+					stringplanmodifier.RequiresReplace(),
+				),
+			},
+			"children": schema.ListNestedAttribute{
+				Description:         "The CHILD ports of the trunk.",
+				MarkdownDescription: "The CHILD ports of the trunk.",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: segmentPortTrunkMemberAttributes(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"context": resourcePolicyContextBlock(),
+		},
+	}
+}
+
+// segmentPortTrunkMemberAttributes describes a port of a trunk. modifiers
+// are applied to its segment and port IDs.
+func segmentPortTrunkMemberAttributes(modifiers ...planmodifier.String) map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"segment_id": schema.StringAttribute{
+			Description:         "Identifier of the segment the port is attached to.",
+			MarkdownDescription: "Identifier of the segment the port is attached to.",
+			Required:            true,
+			PlanModifiers:       modifiers,
+		},
+		"port_id": schema.StringAttribute{
+			Description:         "Identifier for this port.",
+			MarkdownDescription: "Identifier for this port.",
+			Required:            true,
+			PlanModifiers:       modifiers,
+		},
+		"segment_port": schema.SingleNestedAttribute{
+			Description:         "The segment port definition.",
+			MarkdownDescription: "The segment port definition.",
+			Required:            true,
+			Attributes:          segmentPortResourceAttributes(),
+		},
+	}
+}
+
+// trunkInfra builds an H-API request which creates or updates ports and
+// deletes deleted, grouping the ports by segment. The ports are tagged with
+// defaultTags and keep their systemTags, keyed by member.
+func trunkInfra(ports []segmentPortTrunkMember, deleted []segmentPortTrunkMember, defaultTags []client.Tag, systemTags map[string][]client.Tag) client.Infra {
+	var segments []string
+	children := map[string][]client.ChildSegmentPort{}
+	add := func(segment_id string, child client.ChildSegmentPort) {
+		if _, ok := children[segment_id]; !ok {
+			segments = append(segments, segment_id)
+		}
+		children[segment_id] = append(children[segment_id], child)
+	}
+
+	for _, member := range deleted {
+		add(member.SegmentId.ValueString(), client.NewDeletedChildSegmentPort(member.PortId.ValueString()))
+	}
+	for _, member := range ports {
+		// NSX identifies the port by the id in its body rather than a URL
+		port := member.SegmentPort.ToClient()
+		port.Id = member.PortId.ValueString()
+		port.Tags = withDefaultTags(port.Tags, defaultTags, systemTags[member.key()])
+		add(member.SegmentId.ValueString(), client.NewChildSegmentPort(port))
+	}
+
+	infra := client.NewInfra()
+	for _, segment_id := range segments {
+		infra.Children = append(infra.Children, client.NewChildSegment(segment_id, children[segment_id]...))
+	}
+	return infra
+}
+
+// trunkIntentPaths returns the policy paths of ports.
+func trunkIntentPaths(c *client.Client, ports []segmentPortTrunkMember) []string {
+	paths := make([]string, 0, len(ports))
+	for _, member := range ports {
+		paths = append(paths, c.PolicyContext.SegmentPortPath(member.SegmentId.ValueString(), member.PortId.ValueString()))
+	}
+	return paths
+}
+
+// readTrunkComputed reads back the ports of a trunk after it is applied, as
+// H-API responses don't include them, to fill in the attributes NSX sets.
+func readTrunkComputed(ctx context.Context, c *client.Client, trunk *segmentPortTrunkResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	members := []*segmentPortTrunkMember{&trunk.Parent}
+	for i := range trunk.Children {
+		members = append(members, &trunk.Children[i])
+	}
+	for _, member := range members {
+		port, _, d := readSegmentPort(ctx, c, member.SegmentId.ValueString(), member.PortId.ValueString())
+		diags.Append(d...)
+		member.SegmentPort.setComputed(port)
+	}
+	return diags
+}
+
+// readTrunkSystemTags reads the system tags of the ports of a trunk, which
+// aren't in state, keyed by member.
+func readTrunkSystemTags(ctx context.Context, c *client.Client, trunk segmentPortTrunkResourceModel) (map[string][]client.Tag, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	tags := map[string][]client.Tag{}
+	for _, member := range append([]segmentPortTrunkMember{trunk.Parent}, trunk.Children...) {
+		port, _, d := readSegmentPort(ctx, c, member.SegmentId.ValueString(), member.PortId.ValueString())
+		diags.Append(d...)
+		if port != nil {
+			tags[member.key()] = systemTags(port.Tags)
+		}
+	}
+	return tags, diags
+}
+
+// patchTrunk submits infra, reporting any failure with summary.
+func patchTrunk(ctx context.Context, c *client.Client, infra client.Infra, summary string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	rsp, err := c.PatchInfra(ctx, infra)
+	if err != nil {
+		diags.AddError(summary, err.Error())
+		return diags
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		addAPIError(&diags, summary, client.ParseAPIError(rsp), nil)
+	}
+	return diags
+}
+
+// Create a new resource.
+func (r *segmentPortTrunkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	tflog.Debug(ctx, "Preparing to create segment port trunk resource")
+	var plan segmentPortTrunkResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := scopedClient(r.client, plan.Context)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ports := append([]segmentPortTrunkMember{plan.Parent}, plan.Children...)
+	resp.Diagnostics.Append(patchTrunk(ctx, c, trunkInfra(ports, nil, r.defaultTags, nil), "Unable to Create Segment Port Trunk")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = types.StringValue(plan.Parent.key())
+	resp.Diagnostics.Append(readTrunkComputed(ctx, c, &plan)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(waitForRealization(ctx, c, r.realizationTimeout, trunkIntentPaths(c, ports)...)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Debug(ctx, "Created segment port trunk resource", map[string]any{"ports": len(ports)})
+}
+
+// Read resource information.
+func (r *segmentPortTrunkResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	tflog.Debug(ctx, "Preparing to read segment port trunk resource")
+	var state segmentPortTrunkResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := scopedClient(r.client, state.Context)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	parent, _, diags := readSegmentPort(ctx, c, state.Parent.SegmentId.ValueString(), state.Parent.PortId.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Without its parent port the trunk no longer exists
+	if parent == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	state.Parent.SegmentPort = stateSegmentPort(*parent, r.defaultTags, state.Parent.SegmentPort.Tags)
+
+	// Children deleted outside of Terraform are dropped, so that the next
+	// plan adds them back.
+	var children []segmentPortTrunkMember
+	if state.Children != nil {
+		children = make([]segmentPortTrunkMember, 0, len(state.Children))
+	}
+	for _, child := range state.Children {
+		port, _, diags := readSegmentPort(ctx, c, child.SegmentId.ValueString(), child.PortId.ValueString())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if port != nil {
+			child.SegmentPort = stateSegmentPort(*port, r.defaultTags, child.SegmentPort.Tags)
+			children = append(children, child)
+		}
+	}
+	state.Children = children
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Debug(ctx, "Finished reading segment port trunk resource", map[string]any{"success": true})
+}
+
+func (r *segmentPortTrunkResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	tflog.Debug(ctx, "Preparing to update segment port trunk resource")
+	var plan, state segmentPortTrunkResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := scopedClient(r.client, plan.Context)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Children which are no longer configured are deleted in the same
+	// transaction that applies the rest of the plan.
+	planned := map[string]bool{}
+	for _, child := range plan.Children {
+		planned[child.key()] = true
+	}
+	var deleted []segmentPortTrunkMember
+	for _, child := range state.Children {
+		if !planned[child.key()] {
+			deleted = append(deleted, child)
+		}
+	}
+
+	system, diags := readTrunkSystemTags(ctx, c, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ports := append([]segmentPortTrunkMember{plan.Parent}, plan.Children...)
+	resp.Diagnostics.Append(patchTrunk(ctx, c, trunkInfra(ports, deleted, r.defaultTags, system), "Unable to Update Segment Port Trunk")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(readTrunkComputed(ctx, c, &plan)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(waitForRealization(ctx, c, r.realizationTimeout, trunkIntentPaths(c, ports)...)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Debug(ctx, "Updated segment port trunk resource", map[string]any{"ports": len(ports), "deleted": len(deleted)})
+}
+
+func (r *segmentPortTrunkResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	tflog.Debug(ctx, "Preparing to delete segment port trunk resource")
+	var state segmentPortTrunkResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := scopedClient(r.client, state.Context)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleted := append(append([]segmentPortTrunkMember{}, state.Children...), state.Parent)
+	resp.Diagnostics.Append(patchTrunk(ctx, c, trunkInfra(nil, deleted, nil, nil), "Unable to Delete Segment Port Trunk")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Debug(ctx, "Deleted segment port trunk resource", map[string]any{"ports": len(deleted)})
+}
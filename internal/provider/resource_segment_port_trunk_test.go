@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/internal/nsxtest"
+)
+
+const testAccTrunk = "nsxt-intervlan-routing_segment_port_trunk.test"
+
+func testAccTrunkConfig(srv *nsxtest.Server, vlans ...string) string {
+	var children strings.Builder
+	for _, vlan := range vlans {
+		fmt.Fprintf(&children, `
+    {
+      segment_id = "child-segment"
+      port_id    = "child-%[1]s"
+      segment_port = {
+        admin_state = "UP"
+        attachment = {
+          id          = "child-%[1]s"
+          context_id  = "9765bf41-9725-4714-977e-7f7395920de2"
+          traffic_tag = %[1]q
+          type        = "CHILD"
+        }
+        display_name  = "Child port %[1]s"
+        id            = "child-%[1]s"
+        resource_type = "SegmentPort"
+      }
+    },`, vlan)
+	}
+
+	return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "nsxt-intervlan-routing_segment_port_trunk" "test" {
+  parent = {
+    segment_id = "parent-segment"
+    port_id    = "parent-port"
+    segment_port = {
+      admin_state = "UP"
+      attachment = {
+        id   = "9765bf41-9725-4714-977e-7f7395920de2"
+        type = "PARENT"
+      }
+      display_name  = "Parent port"
+      id            = "parent-port"
+      resource_type = "SegmentPort"
+    }
+  }
+  children = [%s
+  ]
+}
+`, children.String())
+}
+
+// testAccCountRequests returns how many requests srv received for method
+// and path.
+func testAccCountRequests(srv *nsxtest.Server, request string) int {
+	count := 0
+	for _, r := range srv.Requests() {
+		if r == request {
+			count++
+		}
+	}
+	return count
+}
+
+func TestAccSegmentPortTrunkResource(t *testing.T) {
+	srv := testAccNewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The parent and every child are created in one transaction.
+			{
+				Config: testAccTrunkConfig(srv, "1001", "1002", "1003"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccTrunk, tfjsonpath.New("id"), knownvalue.StringExact("parent-segment/parent-port")),
+					statecheck.ExpectKnownValue(testAccTrunk, tfjsonpath.New("children"), knownvalue.ListSizeExact(3)),
+				},
+				Check: func(_ *terraform.State) error {
+					if n := testAccCountRequests(srv, "PATCH /policy/api/v1/infra"); n != 1 {
+						return fmt.Errorf("expected 1 H-API request, got %d", n)
+					}
+					if n := testAccCountRequests(srv, "PATCH /policy/api/v1/infra/segments/child-segment/ports/child-1001"); n != 0 {
+						return fmt.Errorf("expected no per-port requests, got %d", n)
+					}
+					return nil
+				},
+			},
+			// Removed children are deleted in the same transaction that adds
+			// new ones.
+			{
+				Config: testAccTrunkConfig(srv, "1001", "1004"),
+				Check: func(_ *terraform.State) error {
+					for port, exists := range map[string]bool{"child-1001": true, "child-1002": false, "child-1003": false, "child-1004": true} {
+						if _, ok := srv.SegmentPort("child-segment", port); ok != exists {
+							return fmt.Errorf("expected %s to exist: %t", port, exists)
+						}
+					}
+					return nil
+				},
+			},
+			// A child deleted outside of Terraform is created again.
+			{
+				PreConfig: func() {
+					srv.DeleteSegmentPort("child-segment", "child-1004")
+				},
+				Config: testAccTrunkConfig(srv, "1001", "1004"),
+				Check: func(_ *terraform.State) error {
+					if _, ok := srv.SegmentPort("child-segment", "child-1004"); !ok {
+						return fmt.Errorf("expected child-1004 to be created again")
+					}
+					return nil
+				},
+			},
+		},
+		CheckDestroy: func(_ *terraform.State) error {
+			for _, port := range []string{"child-1001", "child-1004"} {
+				if _, ok := srv.SegmentPort("child-segment", port); ok {
+					return fmt.Errorf("segment port %s still exists", port)
+				}
+			}
+			if _, ok := srv.SegmentPort("parent-segment", "parent-port"); ok {
+				return fmt.Errorf("segment port parent-port still exists")
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,252 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/internal/nsxtest"
+)
+
+const testAccSegment = "nsxt-intervlan-routing_segment.web"
+
+func testAccSegmentConfig(srv *nsxtest.Server, adminState string) string {
+	return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "nsxt-intervlan-routing_segment" "web" {
+  id                = "web"
+  description       = "Web tier"
+  connectivity_path = "/infra/tier-1s/t1"
+  admin_state       = %q
+  subnets = [
+    {
+      gateway_address = "10.0.1.1/24"
+      dhcp_ranges     = ["10.0.1.100-10.0.1.200"]
+    },
+  ]
+  tags = [
+    {
+      scope = "tier"
+      tag   = "web"
+    },
+  ]
+}
+`, adminState)
+}
+
+func TestAccSegmentResourceLastWriterWins(t *testing.T) {
+	srv := testAccNewServer(t)
+	srv.AddTier1("t1")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSegmentConfig(srv, "UP"),
+			},
+			// Attributes removed from the configuration are removed from the
+			// segment, rather than left unchanged as a PATCH would.
+			{
+				Config: testAccLastWriterWinsConfig(srv) + `
+resource "nsxt-intervlan-routing_segment" "web" {
+  id          = "web"
+  admin_state = "UP"
+  subnets = [
+    {
+      gateway_address = "10.0.1.1/24"
+    },
+  ]
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccSegment, tfjsonpath.New("description"), knownvalue.Null()),
+					statecheck.ExpectKnownValue(testAccSegment, tfjsonpath.New("connectivity_path"), knownvalue.Null()),
+					statecheck.ExpectKnownValue(testAccSegment, tfjsonpath.New("tags"), knownvalue.Null()),
+				},
+				Check: func(_ *terraform.State) error {
+					segment, _ := srv.Segment("web")
+					if segment.Description != "" || segment.ConnectivityPath != "" || len(segment.Tags) != 0 || len(segment.Subnets[0].DhcpRanges) != 0 {
+						return fmt.Errorf("expected the removed attributes to be removed from the segment, got %+v", segment)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccSegmentResource(t *testing.T) {
+	srv := testAccNewServer(t)
+	srv.AddTier1("t1")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSegmentConfig(srv, "UP"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccSegment, tfjsonpath.New("display_name"), knownvalue.StringExact("web")),
+					statecheck.ExpectKnownValue(testAccSegment, tfjsonpath.New("path"), knownvalue.StringExact("/infra/segments/web")),
+					statecheck.ExpectKnownValue(testAccSegment, tfjsonpath.New("subnets").AtSliceIndex(0).AtMapKey("network"), knownvalue.StringExact("10.0.1.0/24")),
+					statecheck.ExpectKnownValue(testAccSegment, tfjsonpath.New("revision"), knownvalue.Int64Exact(0)),
+				},
+			},
+			{
+				ResourceName:      testAccSegment,
+				ImportState:       true,
+				ImportStateId:     "web",
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccSegmentConfig(srv, "DOWN"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(testAccSegment, plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccSegment, tfjsonpath.New("admin_state"), knownvalue.StringExact("DOWN")),
+					statecheck.ExpectKnownValue(testAccSegment, tfjsonpath.New("revision"), knownvalue.Int64Exact(1)),
+				},
+			},
+			// A change made outside of Terraform is detected and reverted.
+			{
+				PreConfig: func() {
+					segment, _ := srv.Segment("web")
+					segment.Description = "changed-out-of-band"
+					srv.SetSegment(segment)
+				},
+				Config: testAccSegmentConfig(srv, "DOWN"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(testAccSegment, plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccSegment, tfjsonpath.New("description"), knownvalue.StringExact("Web tier")),
+				},
+			},
+			// Fields the resource doesn't model are kept when it updates the
+			// segment.
+			{
+				PreConfig: func() {
+					srv.SetObjectField("/infra/segments/web", "replication_mode", "SOURCE")
+				},
+				Config: testAccSegmentConfig(srv, "UP"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccSegment, tfjsonpath.New("admin_state"), knownvalue.StringExact("UP")),
+				},
+				Check: testAccCheckObjectField(srv, "/infra/segments/web", "replication_mode", "SOURCE"),
+			},
+		},
+		CheckDestroy: func(_ *terraform.State) error {
+			if _, ok := srv.Segment("web"); ok {
+				return fmt.Errorf("segment web still exists")
+			}
+			return nil
+		},
+	})
+}
+
+func TestAccSegmentResourceVlan(t *testing.T) {
+	srv := testAccNewServer(t)
+
+	config := func(vlans string) string {
+		return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "nsxt-intervlan-routing_segment" "vlan" {
+  id                  = "vlan"
+  transport_zone_path = "/infra/sites/default/enforcement-points/default/transport-zones/vlan-tz"
+  vlan_ids            = %s
+}
+`, vlans)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(`["100", "4000-4095"]`),
+				ExpectError: regexp.MustCompile(`Invalid VLAN ID`),
+			},
+			{
+				Config: config(`["100", "200-210"]`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("nsxt-intervlan-routing_segment.vlan", tfjsonpath.New("vlan_ids"), knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.StringExact("100"),
+						knownvalue.StringExact("200-210"),
+					})),
+				},
+			},
+			{
+				Config: config(`["100"]`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("nsxt-intervlan-routing_segment.vlan", plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccSegmentResourceInvalid(t *testing.T) {
+	srv := testAccNewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSegmentConfig(srv, "UP"),
+				// The tier-1 gateway doesn't exist in this server.
+				ExpectError: regexp.MustCompile(`connectivity_path /infra/tier-1s/t1 does not exist`),
+			},
+		},
+	})
+}
+
+func TestAccSegmentResourceProject(t *testing.T) {
+	srv := testAccNewServer(t)
+	const segmentPath = "/orgs/default/projects/dev/infra/segments/web"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+resource "nsxt-intervlan-routing_segment" "web" {
+  id          = "web"
+  admin_state = "UP"
+  context {
+    project_id = "dev"
+  }
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccSegment, tfjsonpath.New("path"), knownvalue.StringExact(segmentPath)),
+				},
+			},
+			// Segments outside of the provider context are imported by their
+			// policy path.
+			{
+				ResourceName:      testAccSegment,
+				ImportState:       true,
+				ImportStateId:     segmentPath,
+				ImportStateVerify: true,
+			},
+		},
+		CheckDestroy: func(_ *terraform.State) error {
+			if _, ok := srv.ObjectField(segmentPath, "id"); ok {
+				return fmt.Errorf("segment web still exists in project dev")
+			}
+			return nil
+		},
+	})
+}
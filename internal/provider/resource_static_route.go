@@ -0,0 +1,433 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+var (
+	_ resource.Resource                = &staticRouteResource{}
+	_ resource.ResourceWithConfigure   = &staticRouteResource{}
+	_ resource.ResourceWithImportState = &staticRouteResource{}
+)
+
+func NewStaticRouteResource() resource.Resource {
+	return &staticRouteResource{}
+}
+
+// staticRouteResource manages a static route of a tier-1 gateway, such as
+// one to the networks behind a VM routing through its child ports.
+type staticRouteResource struct {
+	client         *client.Client
+	lastWriterWins bool
+}
+
+type staticRouteResourceModel struct {
+	Id          types.String   `tfsdk:"id"`
+	Tier1Id     types.String   `tfsdk:"tier1_id"`
+	DisplayName types.String   `tfsdk:"display_name"`
+	Description types.String   `tfsdk:"description"`
+	Network     types.String   `tfsdk:"network"`
+	NextHops    []nextHopModel `tfsdk:"next_hops"`
+	Enabled     types.Bool     `tfsdk:"enabled"`
+	Tags        []Tag          `tfsdk:"tags"`
+
+	Path     types.String `tfsdk:"path"`
+	Revision types.Int64  `tfsdk:"revision"`
+
+	Context *policyContextModel `tfsdk:"context"`
+}
+
+type nextHopModel struct {
+	IpAddress     types.String   `tfsdk:"ip_address"`
+	AdminDistance types.Int64    `tfsdk:"admin_distance"`
+	Scope         []types.String `tfsdk:"scope"`
+}
+
+func (r *staticRouteResource) Configure(ctx context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*NsxtIntervlanRoutingProviderData)
+	if !ok {
+		tflog.Error(ctx, "Unable to prepare client")
+		return
+	}
+	r.client = data.Client
+	r.lastWriterWins = data.LastWriterWins
+}
+
+// Metadata returns the resource type name.
+func (r *staticRouteResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_static_route"
+}
+
+func (r *staticRouteResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manage a static route of a tier-1 gateway.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description:         "Identifier of the static route.",
+				MarkdownDescription: "Identifier of the static route.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"tier1_id": schema.StringAttribute{
+				Description:         "Identifier of the tier-1 gateway.",
+				MarkdownDescription: "Identifier of the tier-1 gateway.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"display_name": schema.StringAttribute{
+				Description:         "Display name of the static route. Defaults to the id.",
+				MarkdownDescription: "Display name of the static route. Defaults to the `id`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description:         "Description of the static route.",
+				MarkdownDescription: "Description of the static route.",
+				Optional:            true,
+			},
+			"network": schema.StringAttribute{
+				Description:         "Destination network of the route in CIDR notation, such as 192.168.0.0/16.",
+				MarkdownDescription: "Destination network of the route in CIDR notation, such as `192.168.0.0/16`.",
+				Required:            true,
+				Validators:          []validator.String{cidrValidator{}},
+			},
+			"next_hops": schema.ListNestedAttribute{
+				Description:         "Next hops of the route.",
+				MarkdownDescription: "Next hops of the route.",
+				Required:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"ip_address": schema.StringAttribute{
+							Description:         "IP address of the next hop, such as the address of a child port.",
+							MarkdownDescription: "IP address of the next hop, such as the address of a child port.",
+							Required:            true,
+							Validators:          []validator.String{ipAddressValidator{}},
+						},
+						"admin_distance": schema.Int64Attribute{
+							Description:         "Administrative distance of the next hop, between 1 and 255. Routes with a lower distance are preferred. Defaults to 1.",
+							MarkdownDescription: "Administrative distance of the next hop, between 1 and 255. Routes with a lower distance are preferred. Defaults to `1`.",
+							Optional:            true,
+							Computed:            true,
+							Default:             int64default.StaticInt64(1),
+							Validators:          []validator.Int64{int64RangeValidator{min: 1, max: 255}},
+						},
+						"scope": schema.ListAttribute{
+							Description:         "Policy paths of the gateway interfaces or segments the next hop is reachable through.",
+							MarkdownDescription: "Policy paths of the gateway interfaces or segments the next hop is reachable through.",
+							Optional:            true,
+							ElementType:         types.StringType,
+						},
+					},
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				Description:         "Whether the route is installed on the gateway. Defaults to true.",
+				MarkdownDescription: "Whether the route is installed on the gateway. Defaults to `true`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"tags": resourceTagsAttribute("static route"),
+			"path": schema.StringAttribute{
+				Description:         "Policy path of the static route.",
+				MarkdownDescription: "Policy path of the static route.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"revision": schema.Int64Attribute{
+				Description:         "NSX revision of the static route when it was last read. Updates are rejected if the route has since been modified outside of Terraform.",
+				MarkdownDescription: "NSX revision of the static route when it was last read. Updates are rejected if the route has since been modified outside of Terraform.",
+				Computed:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"context": resourcePolicyContextBlock(),
+		},
+	}
+}
+
+// toClient converts the Terraform model to the static route sent to NSX.
+// system are the tags NSX applied to the route itself, which are kept.
+func (m staticRouteResourceModel) toClient(system []client.Tag) client.StaticRoute {
+	route := client.StaticRoute{
+		Description:  m.Description.ValueString(),
+		DisplayName:  m.DisplayName.ValueString(),
+		Enabled:      m.Enabled.ValueBoolPointer(),
+		Id:           m.Id.ValueString(),
+		Network:      m.Network.ValueString(),
+		ResourceType: "StaticRoutes",
+		Tags:         append(tagsToClient(m.Tags), system...),
+	}
+	for _, hop := range m.NextHops {
+		h := client.RouterNexthop{
+			AdminDistance: hop.AdminDistance.ValueInt64(),
+			IpAddress:     hop.IpAddress.ValueString(),
+		}
+		for _, scope := range hop.Scope {
+			h.Scope = append(h.Scope, scope.ValueString())
+		}
+		route.NextHops = append(route.NextHops, h)
+	}
+	return route
+}
+
+// setStaticRoute copies a static route read from NSX to the model, leaving
+// out the tags which aren't configured on the resource.
+func (m *staticRouteResourceModel) setStaticRoute(route *client.StaticRoute) {
+	m.Id = types.StringValue(route.Id)
+	m.DisplayName = stringValueOrNull(route.DisplayName)
+	m.Description = stringValueOrNull(route.Description)
+	m.Network = types.StringValue(route.Network)
+	m.Enabled = types.BoolPointerValue(route.Enabled)
+	m.Tags = newTags(managedTags(route.Tags, nil, m.Tags))
+	m.Path = stringValueOrNull(route.Path)
+	m.Revision = types.Int64PointerValue(route.Revision)
+
+	m.NextHops = nil
+	for _, hop := range route.NextHops {
+		h := nextHopModel{
+			IpAddress:     types.StringValue(hop.IpAddress),
+			AdminDistance: types.Int64Value(hop.AdminDistance),
+		}
+		for _, scope := range hop.Scope {
+			h.Scope = append(h.Scope, types.StringValue(scope))
+		}
+		m.NextHops = append(m.NextHops, h)
+	}
+}
+
+// readStaticRoute fetches the static route of m from NSX with readObject.
+func readStaticRoute(ctx context.Context, c *client.Client, m staticRouteResourceModel) (*client.StaticRoute, map[string]any, diag.Diagnostics) {
+	return readObject[client.StaticRoute](func() (*http.Response, error) {
+		return c.GetStaticRoute(ctx, m.Tier1Id.ValueString(), m.Id.ValueString())
+	}, "Static Route", staticRouteFieldPaths)
+}
+
+// Create a new resource.
+func (r *staticRouteResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	tflog.Debug(ctx, "Preparing to create static route resource")
+	var plan staticRouteResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := infraClient(r.client, plan.Context, "Tier-1 gateways")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rsp, err := c.PatchStaticRoute(ctx, client.PatchStaticRouteRequest{
+		Tier1Id:     plan.Tier1Id.ValueString(),
+		StaticRoute: plan.toClient(nil),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create Static Route", err.Error())
+		return
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		addAPIError(&resp.Diagnostics, "Unable to Create Static Route", client.ParseAPIError(rsp), staticRouteFieldPaths)
+		return
+	}
+
+	created, _, diags := readStaticRoute(ctx, c, plan)
+	resp.Diagnostics.Append(diags...)
+	if created == nil {
+		resp.Diagnostics.AddError("Unable to Create Static Route", fmt.Sprintf("Static route %s was not found after it was created.", plan.Id.ValueString()))
+		return
+	}
+	plan.setStaticRoute(created)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	tflog.Debug(ctx, "Created static route resource", map[string]any{"success": true})
+}
+
+// Read resource information.
+func (r *staticRouteResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	tflog.Debug(ctx, "Preparing to read static route resource")
+	var state staticRouteResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := infraClient(r.client, state.Context, "Tier-1 gateways")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	route, _, diags := readStaticRoute(ctx, c, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Treat HTTP 404 Not Found status as a signal to remove/recreate resource
+	if route == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	state.setStaticRoute(route)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Debug(ctx, "Finished reading static route resource", map[string]any{"success": true})
+}
+
+func (r *staticRouteResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	tflog.Debug(ctx, "Preparing to update static route resource")
+	var plan, state staticRouteResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := infraClient(r.client, plan.Context, "Tier-1 gateways")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	current, currentObject, diags := readStaticRoute(ctx, c, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var system []client.Tag
+	var revision *int64
+	if current != nil {
+		system = systemTags(current.Tags)
+		revision = current.Revision
+	}
+	route := plan.toClient(system)
+	route.Revision = updateRevision(state.Revision, revision, r.lastWriterWins)
+
+	rsp, err := c.UpdateStaticRoute(ctx, client.UpdateStaticRouteRequest{Tier1Id: plan.Tier1Id.ValueString(), StaticRoute: route, Current: currentObject})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Update Static Route", err.Error())
+		return
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode == http.StatusPreconditionFailed {
+		// Refresh state from NSX, so that the next plan shows what changed.
+		current, _, diags := readStaticRoute(ctx, c, state)
+		resp.Diagnostics.Append(diags...)
+		if current == nil {
+			addRevisionConflict(&resp.Diagnostics, "Static Route", plan.Id.ValueString(), state.Revision.ValueInt64(), nil, "")
+			return
+		}
+		addRevisionConflict(&resp.Diagnostics, "Static Route", plan.Id.ValueString(), state.Revision.ValueInt64(), current.Revision, current.LastModifiedUser)
+		state.setStaticRoute(current)
+		resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+		return
+	}
+	if rsp.StatusCode != http.StatusOK {
+		addAPIError(&resp.Diagnostics, "Unable to Update Static Route", client.ParseAPIError(rsp), staticRouteFieldPaths)
+		return
+	}
+
+	updated, _, diags := readStaticRoute(ctx, c, plan)
+	resp.Diagnostics.Append(diags...)
+	if updated == nil {
+		resp.Diagnostics.AddError("Unable to Update Static Route", fmt.Sprintf("Static route %s was not found after it was updated.", plan.Id.ValueString()))
+		return
+	}
+	plan.setStaticRoute(updated)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	tflog.Debug(ctx, "Updated static route resource", map[string]any{"success": true})
+}
+
+func (r *staticRouteResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	tflog.Debug(ctx, "Preparing to delete static route resource")
+	var state staticRouteResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := infraClient(r.client, state.Context, "Tier-1 gateways")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rsp, err := c.DeleteStaticRoute(ctx, state.Tier1Id.ValueString(), state.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Delete Static Route", err.Error())
+		return
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK && rsp.StatusCode != http.StatusNotFound {
+		addAPIError(&resp.Diagnostics, "Unable to Delete Static Route", client.ParseAPIError(rsp), nil)
+		return
+	}
+	tflog.Debug(ctx, "Deleted static route resource", map[string]any{"success": true})
+}
+
+func (r *staticRouteResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Routes in another project are imported by their policy path.
+	if strings.HasPrefix(req.ID, "/") {
+		pc, tier1_id, route_id, err := client.ParseStaticRoutePath(req.ID)
+		if err != nil {
+			resp.Diagnostics.AddError("Unexpected Import Identifier", err.Error())
+			return
+		}
+		resp.Diagnostics.Append(importContext(ctx, &resp.State, r.client, pc, req.ID)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tier1_id"), tier1_id)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), route_id)...)
+		return
+	}
+
+	// A route is only unique within its gateway, so import IDs have the form
+	// <tier1_id>/<id>.
+	tier1_id, route_id, ok := strings.Cut(req.ID, "/")
+	if !ok || tier1_id == "" || route_id == "" || strings.Contains(route_id, "/") {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: tier1_id/id or a static route policy path. Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tier1_id"), tier1_id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), route_id)...)
+}
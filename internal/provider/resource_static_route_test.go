@@ -0,0 +1,191 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/internal/nsxtest"
+)
+
+const testAccStaticRoute = "nsxt-intervlan-routing_static_route.behind_fw"
+
+func testAccStaticRouteConfig(srv *nsxtest.Server, network string, body string) string {
+	return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "nsxt-intervlan-routing_static_route" "behind_fw" {
+  id       = "behind-fw"
+  tier1_id = "t1"
+  network  = %q
+  %s
+}
+`, network, body)
+}
+
+func TestAccStaticRouteResource(t *testing.T) {
+	srv := testAccNewServer(t)
+	srv.AddTier1("t1")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStaticRouteConfig(srv, "192.168.0.0/16", `
+  next_hops = [
+    {
+      ip_address = "10.0.100.254"
+    },
+  ]`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccStaticRoute, tfjsonpath.New("enabled"), knownvalue.Bool(true)),
+					statecheck.ExpectKnownValue(testAccStaticRoute, tfjsonpath.New("next_hops").AtSliceIndex(0).AtMapKey("admin_distance"), knownvalue.Int64Exact(1)),
+					statecheck.ExpectKnownValue(testAccStaticRoute, tfjsonpath.New("path"), knownvalue.StringExact("/infra/tier-1s/t1/static-routes/behind-fw")),
+				},
+			},
+			{
+				ResourceName:      testAccStaticRoute,
+				ImportState:       true,
+				ImportStateId:     "t1/behind-fw",
+				ImportStateVerify: true,
+			},
+			// A second next hop is only used when the first is unreachable.
+			{
+				Config: testAccStaticRouteConfig(srv, "192.168.0.0/16", `
+  enabled = false
+  next_hops = [
+    {
+      ip_address = "10.0.100.254"
+      scope      = ["/infra/tier-1s/t1/locale-services/default/interfaces/vlan-100"]
+    },
+    {
+      ip_address     = "10.0.101.254"
+      admin_distance = 2
+    },
+  ]`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(testAccStaticRoute, plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccStaticRoute, tfjsonpath.New("enabled"), knownvalue.Bool(false)),
+					statecheck.ExpectKnownValue(testAccStaticRoute, tfjsonpath.New("next_hops").AtSliceIndex(1).AtMapKey("admin_distance"), knownvalue.Int64Exact(2)),
+					statecheck.ExpectKnownValue(testAccStaticRoute, tfjsonpath.New("revision"), knownvalue.Int64Exact(1)),
+				},
+			},
+			// Fields the resource doesn't model are kept when it updates the
+			// route.
+			{
+				PreConfig: func() {
+					srv.SetObjectField("/infra/tier-1s/t1/static-routes/behind-fw", "enabled_on_secondary", true)
+				},
+				Config: testAccStaticRouteConfig(srv, "192.168.0.0/16", `
+  next_hops = [
+    {
+      ip_address = "10.0.100.254"
+    },
+  ]`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccStaticRoute, tfjsonpath.New("enabled"), knownvalue.Bool(true)),
+				},
+				Check: testAccCheckObjectField(srv, "/infra/tier-1s/t1/static-routes/behind-fw", "enabled_on_secondary", true),
+			},
+		},
+		CheckDestroy: func(_ *terraform.State) error {
+			if _, ok := srv.StaticRoute("t1", "behind-fw"); ok {
+				return fmt.Errorf("static route behind-fw still exists")
+			}
+			return nil
+		},
+	})
+}
+
+func TestAccStaticRouteResourceInvalid(t *testing.T) {
+	srv := testAccNewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStaticRouteConfig(srv, "192.168.1.1/16", `
+  next_hops = [
+    {
+      ip_address = "10.0.100.254"
+    },
+  ]`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`value must be a network in CIDR notation`),
+			},
+			{
+				Config: testAccStaticRouteConfig(srv, "192.168.0.0/16", `
+  next_hops = [
+    {
+      ip_address = "firewall"
+    },
+  ]`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`value must be an IP address`),
+			},
+			{
+				Config: testAccStaticRouteConfig(srv, "192.168.0.0/16", `
+  next_hops = [
+    {
+      ip_address     = "10.0.100.254"
+      admin_distance = 0
+    },
+  ]`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`value must be between 1 and 255`),
+			},
+		},
+	})
+}
+
+func TestAccStaticRouteResourceProject(t *testing.T) {
+	srv := testAccNewServer(t)
+	srv.AddTier1At(client.PolicyContext{ProjectId: "dev"}, "t1")
+	const routePath = "/orgs/default/projects/dev/infra/tier-1s/t1/static-routes/behind-fw"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStaticRouteConfig(srv, "192.168.0.0/16", `
+  next_hops = [
+    {
+      ip_address = "10.0.100.254"
+    },
+  ]
+  context {
+    project_id = "dev"
+  }`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccStaticRoute, tfjsonpath.New("path"), knownvalue.StringExact(routePath)),
+				},
+			},
+			// Routes outside of the provider context are imported by their
+			// policy path.
+			{
+				ResourceName:      testAccStaticRoute,
+				ImportState:       true,
+				ImportStateId:     routePath,
+				ImportStateVerify: true,
+			},
+		},
+		CheckDestroy: func(_ *terraform.State) error {
+			if _, ok := srv.ObjectField(routePath, "id"); ok {
+				return fmt.Errorf("static route behind-fw still exists in project dev")
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,448 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+)
+
+var (
+	_ resource.Resource                = &tier1InterfaceResource{}
+	_ resource.ResourceWithConfigure   = &tier1InterfaceResource{}
+	_ resource.ResourceWithImportState = &tier1InterfaceResource{}
+)
+
+func NewTier1InterfaceResource() resource.Resource {
+	return &tier1InterfaceResource{}
+}
+
+// tier1InterfaceResource manages a service interface of a tier-1 gateway,
+// the routed leg of a VLAN segment.
+type tier1InterfaceResource struct {
+	client         *client.Client
+	lastWriterWins bool
+}
+
+type tier1InterfaceResourceModel struct {
+	Id              types.String           `tfsdk:"id"`
+	Tier1Id         types.String           `tfsdk:"tier1_id"`
+	LocaleServiceId types.String           `tfsdk:"locale_service_id"`
+	DisplayName     types.String           `tfsdk:"display_name"`
+	Description     types.String           `tfsdk:"description"`
+	SegmentPath     types.String           `tfsdk:"segment_path"`
+	Subnets         []interfaceSubnetModel `tfsdk:"subnets"`
+	Mtu             types.Int64            `tfsdk:"mtu"`
+	UrpfMode        types.String           `tfsdk:"urpf_mode"`
+	Tags            []Tag                  `tfsdk:"tags"`
+
+	Path     types.String `tfsdk:"path"`
+	Revision types.Int64  `tfsdk:"revision"`
+
+	Context *policyContextModel `tfsdk:"context"`
+}
+
+type interfaceSubnetModel struct {
+	IpAddresses []types.String `tfsdk:"ip_addresses"`
+	PrefixLen   types.Int64    `tfsdk:"prefix_len"`
+}
+
+func (r *tier1InterfaceResource) Configure(ctx context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*NsxtIntervlanRoutingProviderData)
+	if !ok {
+		tflog.Error(ctx, "Unable to prepare client")
+		return
+	}
+	r.client = data.Client
+	r.lastWriterWins = data.LastWriterWins
+}
+
+// Metadata returns the resource type name.
+func (r *tier1InterfaceResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tier1_interface"
+}
+
+func (r *tier1InterfaceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manage a service interface of a tier-1 gateway, which routes a segment such as a VLAN backed segment.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description:         "Identifier of the interface.",
+				MarkdownDescription: "Identifier of the interface.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"tier1_id": schema.StringAttribute{
+				Description:         "Identifier of the tier-1 gateway.",
+				MarkdownDescription: "Identifier of the tier-1 gateway.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"locale_service_id": schema.StringAttribute{
+				Description:         "Identifier of the tier-1 gateway's locale service. Defaults to default.",
+				MarkdownDescription: "Identifier of the tier-1 gateway's locale service. Defaults to `default`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("default"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"display_name": schema.StringAttribute{
+				Description:         "Display name of the interface. Defaults to the id.",
+				MarkdownDescription: "Display name of the interface. Defaults to the `id`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description:         "Description of the interface.",
+				MarkdownDescription: "Description of the interface.",
+				Optional:            true,
+			},
+			"segment_path": schema.StringAttribute{
+				Description:         "Policy path of the segment the interface is connected to, such as /infra/segments/<id>. The segment must not have a connectivity_path. Changing it creates a new interface.",
+				MarkdownDescription: "Policy path of the segment the interface is connected to, such as `/infra/segments/<id>`. The segment must not have a `connectivity_path`. Changing it creates a new interface.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"subnets": schema.ListNestedAttribute{
+				Description:         "Addresses of the interface on the segment.",
+				MarkdownDescription: "Addresses of the interface on the segment.",
+				Required:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"ip_addresses": schema.ListAttribute{
+							Description:         "IP addresses of the interface in the subnet, such as 10.0.100.1.",
+							MarkdownDescription: "IP addresses of the interface in the subnet, such as `10.0.100.1`.",
+							Required:            true,
+							ElementType:         types.StringType,
+						},
+						"prefix_len": schema.Int64Attribute{
+							Description:         "Prefix length of the subnet, such as 24.",
+							MarkdownDescription: "Prefix length of the subnet, such as `24`.",
+							Required:            true,
+						},
+					},
+				},
+			},
+			"mtu": schema.Int64Attribute{
+				Description:         "Maximum transmission unit of the interface. Defaults to the gateway's MTU.",
+				MarkdownDescription: "Maximum transmission unit of the interface. Defaults to the gateway's MTU.",
+				Optional:            true,
+			},
+			"urpf_mode": schema.StringAttribute{
+				Description:         "Unicast reverse path forwarding mode of the interface. Either STRICT or NONE. Defaults to STRICT.",
+				MarkdownDescription: "Unicast reverse path forwarding mode of the interface. Either `STRICT` or `NONE`. Defaults to `STRICT`.",
+				Optional:            true,
+				Computed:            true,
+				Validators:          []validator.String{urpfModeValidator()},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tags": resourceTagsAttribute("interface"),
+			"path": schema.StringAttribute{
+				Description:         "Policy path of the interface.",
+				MarkdownDescription: "Policy path of the interface.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"revision": schema.Int64Attribute{
+				Description:         "NSX revision of the interface when it was last read. Updates are rejected if the interface has since been modified outside of Terraform.",
+				MarkdownDescription: "NSX revision of the interface when it was last read. Updates are rejected if the interface has since been modified outside of Terraform.",
+				Computed:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"context": resourcePolicyContextBlock(),
+		},
+	}
+}
+
+// toClient converts the Terraform model to the interface sent to NSX.
+// system are the tags NSX applied to the interface itself, which are kept.
+func (m tier1InterfaceResourceModel) toClient(system []client.Tag) client.Tier1Interface {
+	iface := client.Tier1Interface{
+		Description:  m.Description.ValueString(),
+		DisplayName:  m.DisplayName.ValueString(),
+		Id:           m.Id.ValueString(),
+		Mtu:          m.Mtu.ValueInt64Pointer(),
+		ResourceType: "Tier1Interface",
+		SegmentPath:  m.SegmentPath.ValueString(),
+		Tags:         append(tagsToClient(m.Tags), system...),
+		UrpfMode:     m.UrpfMode.ValueString(),
+	}
+	for _, subnet := range m.Subnets {
+		s := client.InterfaceSubnet{PrefixLen: subnet.PrefixLen.ValueInt64()}
+		for _, ip := range subnet.IpAddresses {
+			s.IpAddresses = append(s.IpAddresses, ip.ValueString())
+		}
+		iface.Subnets = append(iface.Subnets, s)
+	}
+	return iface
+}
+
+// setInterface copies an interface read from NSX to the model, leaving out
+// the tags which aren't configured on the resource.
+func (m *tier1InterfaceResourceModel) setInterface(iface *client.Tier1Interface) {
+	m.Id = types.StringValue(iface.Id)
+	m.DisplayName = stringValueOrNull(iface.DisplayName)
+	m.Description = stringValueOrNull(iface.Description)
+	m.SegmentPath = types.StringValue(iface.SegmentPath)
+	m.Mtu = types.Int64PointerValue(iface.Mtu)
+	m.UrpfMode = stringValueOrNull(iface.UrpfMode)
+	m.Tags = newTags(managedTags(iface.Tags, nil, m.Tags))
+	m.Path = stringValueOrNull(iface.Path)
+	m.Revision = types.Int64PointerValue(iface.Revision)
+
+	m.Subnets = nil
+	for _, subnet := range iface.Subnets {
+		s := interfaceSubnetModel{PrefixLen: types.Int64Value(subnet.PrefixLen)}
+		for _, ip := range subnet.IpAddresses {
+			s.IpAddresses = append(s.IpAddresses, types.StringValue(ip))
+		}
+		m.Subnets = append(m.Subnets, s)
+	}
+}
+
+// readTier1Interface fetches the interface of m from NSX with readObject.
+func readTier1Interface(ctx context.Context, c *client.Client, m tier1InterfaceResourceModel) (*client.Tier1Interface, map[string]any, diag.Diagnostics) {
+	return readObject[client.Tier1Interface](func() (*http.Response, error) {
+		return c.GetTier1Interface(ctx, m.Tier1Id.ValueString(), m.LocaleServiceId.ValueString(), m.Id.ValueString())
+	}, "Tier-1 Interface", tier1InterfaceFieldPaths)
+}
+
+// Create a new resource.
+func (r *tier1InterfaceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	tflog.Debug(ctx, "Preparing to create tier-1 interface resource")
+	var plan tier1InterfaceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := infraClient(r.client, plan.Context, "Tier-1 gateways")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rsp, err := c.PatchTier1Interface(ctx, client.PatchTier1InterfaceRequest{
+		Tier1Id:         plan.Tier1Id.ValueString(),
+		LocaleServiceId: plan.LocaleServiceId.ValueString(),
+		Interface:       plan.toClient(nil),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create Tier-1 Interface", err.Error())
+		return
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		addAPIError(&resp.Diagnostics, "Unable to Create Tier-1 Interface", client.ParseAPIError(rsp), tier1InterfaceFieldPaths)
+		return
+	}
+
+	created, _, diags := readTier1Interface(ctx, c, plan)
+	resp.Diagnostics.Append(diags...)
+	if created == nil {
+		resp.Diagnostics.AddError("Unable to Create Tier-1 Interface", fmt.Sprintf("Interface %s was not found after it was created.", plan.Id.ValueString()))
+		return
+	}
+	plan.setInterface(created)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	tflog.Debug(ctx, "Created tier-1 interface resource", map[string]any{"success": true})
+}
+
+// Read resource information.
+func (r *tier1InterfaceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	tflog.Debug(ctx, "Preparing to read tier-1 interface resource")
+	var state tier1InterfaceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := infraClient(r.client, state.Context, "Tier-1 gateways")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	iface, _, diags := readTier1Interface(ctx, c, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Treat HTTP 404 Not Found status as a signal to remove/recreate resource
+	if iface == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	state.setInterface(iface)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Debug(ctx, "Finished reading tier-1 interface resource", map[string]any{"success": true})
+}
+
+func (r *tier1InterfaceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	tflog.Debug(ctx, "Preparing to update tier-1 interface resource")
+	var plan, state tier1InterfaceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := infraClient(r.client, plan.Context, "Tier-1 gateways")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	current, currentObject, diags := readTier1Interface(ctx, c, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var system []client.Tag
+	var revision *int64
+	if current != nil {
+		system = systemTags(current.Tags)
+		revision = current.Revision
+	}
+	iface := plan.toClient(system)
+	iface.Revision = updateRevision(state.Revision, revision, r.lastWriterWins)
+
+	rsp, err := c.UpdateTier1Interface(ctx, client.UpdateTier1InterfaceRequest{
+		Tier1Id:         plan.Tier1Id.ValueString(),
+		LocaleServiceId: plan.LocaleServiceId.ValueString(),
+		Interface:       iface,
+		Current:         currentObject,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Update Tier-1 Interface", err.Error())
+		return
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode == http.StatusPreconditionFailed {
+		// Refresh state from NSX, so that the next plan shows what changed.
+		current, _, diags := readTier1Interface(ctx, c, state)
+		resp.Diagnostics.Append(diags...)
+		if current == nil {
+			addRevisionConflict(&resp.Diagnostics, "Tier-1 Interface", plan.Id.ValueString(), state.Revision.ValueInt64(), nil, "")
+			return
+		}
+		addRevisionConflict(&resp.Diagnostics, "Tier-1 Interface", plan.Id.ValueString(), state.Revision.ValueInt64(), current.Revision, current.LastModifiedUser)
+		state.setInterface(current)
+		resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+		return
+	}
+	if rsp.StatusCode != http.StatusOK {
+		addAPIError(&resp.Diagnostics, "Unable to Update Tier-1 Interface", client.ParseAPIError(rsp), tier1InterfaceFieldPaths)
+		return
+	}
+
+	updated, _, diags := readTier1Interface(ctx, c, plan)
+	resp.Diagnostics.Append(diags...)
+	if updated == nil {
+		resp.Diagnostics.AddError("Unable to Update Tier-1 Interface", fmt.Sprintf("Interface %s was not found after it was updated.", plan.Id.ValueString()))
+		return
+	}
+	plan.setInterface(updated)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	tflog.Debug(ctx, "Updated tier-1 interface resource", map[string]any{"success": true})
+}
+
+func (r *tier1InterfaceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	tflog.Debug(ctx, "Preparing to delete tier-1 interface resource")
+	var state tier1InterfaceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := infraClient(r.client, state.Context, "Tier-1 gateways")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rsp, err := c.DeleteTier1Interface(ctx, state.Tier1Id.ValueString(), state.LocaleServiceId.ValueString(), state.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Delete Tier-1 Interface", err.Error())
+		return
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK && rsp.StatusCode != http.StatusNotFound {
+		addAPIError(&resp.Diagnostics, "Unable to Delete Tier-1 Interface", client.ParseAPIError(rsp), nil)
+		return
+	}
+	tflog.Debug(ctx, "Deleted tier-1 interface resource", map[string]any{"success": true})
+}
+
+func (r *tier1InterfaceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Interfaces in another project are imported by their policy path.
+	if strings.HasPrefix(req.ID, "/") {
+		pc, tier1_id, locale_service_id, interface_id, err := client.ParseTier1InterfacePath(req.ID)
+		if err != nil {
+			resp.Diagnostics.AddError("Unexpected Import Identifier", err.Error())
+			return
+		}
+		resp.Diagnostics.Append(importContext(ctx, &resp.State, r.client, pc, req.ID)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tier1_id"), tier1_id)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("locale_service_id"), locale_service_id)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), interface_id)...)
+		return
+	}
+
+	// An interface is only unique within its locale service, so import IDs
+	// have the form <tier1_id>/<locale_service_id>/<id>.
+	parts := strings.Split(req.ID, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: tier1_id/locale_service_id/id or a tier-1 interface policy path. Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tier1_id"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("locale_service_id"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), parts[2])...)
+}
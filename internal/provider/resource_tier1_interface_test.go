@@ -0,0 +1,171 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/internal/nsxtest"
+)
+
+const testAccTier1Interface = "nsxt-intervlan-routing_tier1_interface.vlan_100"
+
+func testAccTier1InterfaceConfig(srv *nsxtest.Server, extra string) string {
+	return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "nsxt-intervlan-routing_segment" "vlan_100" {
+  id                  = "vlan-100"
+  transport_zone_path = "/infra/sites/default/enforcement-points/default/transport-zones/vlan-tz"
+  vlan_ids            = ["100"]
+}
+
+resource "nsxt-intervlan-routing_tier1_interface" "vlan_100" {
+  id           = "vlan-100"
+  tier1_id     = "t1"
+  segment_path = nsxt-intervlan-routing_segment.vlan_100.path
+  subnets = [
+    {
+      ip_addresses = ["10.0.100.1"]
+      prefix_len   = 24
+    },
+  ]
+  %s
+}
+`, extra)
+}
+
+func TestAccTier1InterfaceResource(t *testing.T) {
+	srv := testAccNewServer(t)
+	srv.AddTier1("t1")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTier1InterfaceConfig(srv, ""),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccTier1Interface, tfjsonpath.New("locale_service_id"), knownvalue.StringExact("default")),
+					statecheck.ExpectKnownValue(testAccTier1Interface, tfjsonpath.New("segment_path"), knownvalue.StringExact("/infra/segments/vlan-100")),
+					statecheck.ExpectKnownValue(testAccTier1Interface, tfjsonpath.New("urpf_mode"), knownvalue.StringExact("STRICT")),
+					statecheck.ExpectKnownValue(testAccTier1Interface, tfjsonpath.New("path"), knownvalue.StringExact("/infra/tier-1s/t1/locale-services/default/interfaces/vlan-100")),
+					statecheck.ExpectKnownValue(testAccTier1Interface, tfjsonpath.New("mtu"), knownvalue.Null()),
+				},
+			},
+			{
+				ResourceName:      testAccTier1Interface,
+				ImportState:       true,
+				ImportStateId:     "t1/default/vlan-100",
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccTier1InterfaceConfig(srv, `
+  mtu       = 9000
+  urpf_mode = "NONE"`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(testAccTier1Interface, plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccTier1Interface, tfjsonpath.New("mtu"), knownvalue.Int64Exact(9000)),
+					statecheck.ExpectKnownValue(testAccTier1Interface, tfjsonpath.New("urpf_mode"), knownvalue.StringExact("NONE")),
+					statecheck.ExpectKnownValue(testAccTier1Interface, tfjsonpath.New("revision"), knownvalue.Int64Exact(1)),
+				},
+			},
+			// Fields the resource doesn't model are kept when it updates the
+			// interface.
+			{
+				PreConfig: func() {
+					srv.SetObjectField("/infra/tier-1s/t1/locale-services/default/interfaces/vlan-100", "dhcp_relay_path", "/infra/dhcp-relay-configs/relay")
+				},
+				Config: testAccTier1InterfaceConfig(srv, `
+  mtu       = 1500
+  urpf_mode = "NONE"`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccTier1Interface, tfjsonpath.New("mtu"), knownvalue.Int64Exact(1500)),
+				},
+				Check: testAccCheckObjectField(srv, "/infra/tier-1s/t1/locale-services/default/interfaces/vlan-100", "dhcp_relay_path", "/infra/dhcp-relay-configs/relay"),
+			},
+		},
+		CheckDestroy: func(_ *terraform.State) error {
+			if _, ok := srv.Tier1Interface("t1", nsxtest.DefaultLocaleServiceId, "vlan-100"); ok {
+				return fmt.Errorf("interface vlan-100 still exists")
+			}
+			return nil
+		},
+	})
+}
+
+func TestAccTier1InterfaceResourceInvalid(t *testing.T) {
+	srv := testAccNewServer(t)
+	srv.AddTier1("t1")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccTier1InterfaceConfig(srv, `urpf_mode = "LOOSE"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`value must be one of STRICT, NONE`),
+			},
+		},
+	})
+}
+
+func TestAccTier1InterfaceResourceProject(t *testing.T) {
+	srv := testAccNewServer(t)
+	project := client.PolicyContext{ProjectId: "dev"}
+	srv.AddTier1At(project, "t1")
+	srv.AddSegmentAt(project, "vlan-100")
+	const interfacePath = "/orgs/default/projects/dev/infra/tier-1s/t1/locale-services/default/interfaces/vlan-100"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+resource "nsxt-intervlan-routing_tier1_interface" "vlan_100" {
+  id           = "vlan-100"
+  tier1_id     = "t1"
+  segment_path = "/orgs/default/projects/dev/infra/segments/vlan-100"
+  subnets = [
+    {
+      ip_addresses = ["10.0.100.1"]
+      prefix_len   = 24
+    },
+  ]
+  context {
+    project_id = "dev"
+  }
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(testAccTier1Interface, tfjsonpath.New("path"), knownvalue.StringExact(interfacePath)),
+				},
+			},
+			// Interfaces outside of the provider context are imported by
+			// their policy path.
+			{
+				ResourceName:      testAccTier1Interface,
+				ImportState:       true,
+				ImportStateId:     interfacePath,
+				ImportStateVerify: true,
+			},
+		},
+		CheckDestroy: func(_ *terraform.State) error {
+			if _, ok := srv.ObjectField(interfacePath, "id"); ok {
+				return fmt.Errorf("tier-1 interface vlan-100 still exists in project dev")
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,181 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/client"
+
+	datasourceschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+// systemTagScopePrefixes are the scopes of the tags NSX and its integrations,
+// such as NCP, apply to objects themselves. They are left out of state, and
+// kept when Terraform updates an object.
+var systemTagScopePrefixes = []string{"nsx-", "nsx/", "ncp/"}
+
+func isSystemTag(tag client.Tag) bool {
+	scope := strings.ToLower(tag.Scope)
+	for _, prefix := range systemTagScopePrefixes {
+		if strings.HasPrefix(scope, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// systemTags returns the system tags among the tags of an object read from
+// NSX. As they aren't in state, updates read the object first and send its
+// system tags back, so that they're kept.
+func systemTags(tags []client.Tag) []client.Tag {
+	var system []client.Tag
+	for _, tag := range tags {
+		if isSystemTag(tag) {
+			system = append(system, tag)
+		}
+	}
+	return system
+}
+
+// withDefaultTags adds the provider's default tags to the tags of an object,
+// except those with a scope the object already has a tag for, and the
+// object's system tags. A default tag without a scope is only left out if
+// the object already has the same tag.
+func withDefaultTags(tags []client.Tag, defaults []client.Tag, system []client.Tag) []client.Tag {
+	scopes := map[string]bool{}
+	existing := map[client.Tag]bool{}
+	for _, tag := range tags {
+		if tag.Scope != "" {
+			scopes[tag.Scope] = true
+		}
+		existing[tag] = true
+	}
+	for _, tag := range defaults {
+		if !scopes[tag.Scope] && !existing[tag] {
+			tags = append(tags, tag)
+		}
+	}
+	return append(tags, system...)
+}
+
+// managedTags returns the tags of an object read from NSX which are
+// configured on the resource. System tags are left out, as are default tags unless
+// they're also in prior, the tags in state.
+func managedTags(tags []client.Tag, defaults []client.Tag, prior []Tag) []client.Tag {
+	configured := map[client.Tag]bool{}
+	for _, tag := range tagsToClient(prior) {
+		configured[tag] = true
+	}
+	isDefault := map[client.Tag]bool{}
+	for _, tag := range defaults {
+		isDefault[tag] = true
+	}
+
+	var managed []client.Tag
+	for _, tag := range tags {
+		if isSystemTag(tag) || (isDefault[tag] && !configured[tag]) {
+			continue
+		}
+		managed = append(managed, tag)
+	}
+	return managed
+}
+
+func newTags(tags []client.Tag) []Tag {
+	var entries []Tag
+	for _, tag := range tags {
+		entries = append(entries, Tag{
+			Scope: stringValueOrNull(tag.Scope),
+			Tag:   types.StringValue(tag.Tag),
+		})
+	}
+	return entries
+}
+
+func tagsToClient(entries []Tag) []client.Tag {
+	var tags []client.Tag
+	for _, entry := range entries {
+		tags = append(tags, client.Tag{
+			Scope: entry.Scope.ValueString(),
+			Tag:   entry.Tag.ValueString(),
+		})
+	}
+	return tags
+}
+
+// tagFilter matches objects with a tag of the given scope and value. An unset
+// scope or tag matches any.
+type tagFilter struct {
+	Scope types.String `tfsdk:"scope"`
+	Tag   types.String `tfsdk:"tag"`
+}
+
+// resourceTagsAttribute describes the tags of the objects a resource
+// manages, other than segment ports whose tags are part of segment_port.
+func resourceTagsAttribute(objects string) resourceschema.SetNestedAttribute {
+	description := "NSX tags of the " + objects + ". Tags with a scope starting with nsx-, nsx/ or ncp/ are managed by NSX and its integrations, and are ignored."
+	markdownDescription := "NSX tags of the " + objects + ". Tags with a scope starting with `nsx-`, `nsx/` or `ncp/` are managed by NSX and its integrations, and are ignored."
+	return resourceschema.SetNestedAttribute{
+		Description:         description,
+		MarkdownDescription: markdownDescription,
+		Optional:            true,
+		NestedObject: resourceschema.NestedAttributeObject{
+			Attributes: map[string]resourceschema.Attribute{
+				"scope": resourceschema.StringAttribute{
+					Description:         "Scope of the tag",
+					MarkdownDescription: "Scope of the tag",
+					Optional:            true,
+				},
+				"tag": resourceschema.StringAttribute{
+					Description:         "Value of the tag",
+					MarkdownDescription: "Value of the tag",
+					Required:            true,
+				},
+			},
+		},
+	}
+}
+
+// tagFilterAttribute describes the tags a data source filters objects by.
+func tagFilterAttribute(objects string) datasourceschema.SetNestedAttribute {
+	return datasourceschema.SetNestedAttribute{
+		Description: "Only return " + objects + " with a tag matching each of these. A filter without a scope or tag matches any.",
+		Optional:    true,
+		NestedObject: datasourceschema.NestedAttributeObject{
+			Attributes: map[string]datasourceschema.Attribute{
+				"scope": datasourceschema.StringAttribute{
+					Description: "Scope of the tag",
+					Optional:    true,
+				},
+				"tag": datasourceschema.StringAttribute{
+					Description: "Value of the tag",
+					Optional:    true,
+				},
+			},
+		},
+	}
+}
+
+func (f tagFilter) matches(tags []client.Tag) bool {
+	for _, tag := range tags {
+		if (f.Scope.IsNull() || f.Scope.ValueString() == tag.Scope) &&
+			(f.Tag.IsNull() || f.Tag.ValueString() == tag.Tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesTagFilters reports whether tags match every filter.
+func matchesTagFilters(tags []client.Tag, filters []tagFilter) bool {
+	for _, f := range filters {
+		if !f.matches(tags) {
+			return false
+		}
+	}
+	return true
+}
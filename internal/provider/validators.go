@@ -0,0 +1,254 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ validator.String = int64StringValidator{}
+
+// int64StringValidator checks that a string attribute holds an integer, for
+// numeric NSX fields which the schema exposes as strings.
+type int64StringValidator struct {
+	min, max int64
+}
+
+func (v int64StringValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be an integer between %d and %d", v.min, v.max)
+}
+
+func (v int64StringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v int64StringValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	i, err := strconv.ParseInt(req.ConfigValue.ValueString(), 10, 64)
+	if err != nil || i < v.min || i > v.max {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+// vlanIdValidator checks a VLAN ID held in a string attribute.
+func vlanIdValidator() validator.String {
+	return int64StringValidator{min: 0, max: 4094}
+}
+
+var (
+	_ validator.List = vlanRangesValidator{}
+	_ validator.Set  = vlanRangesValidator{}
+)
+
+// vlanRangesValidator checks that every element of a list or set attribute
+// holds a VLAN ID or an inclusive range of them, as in the vlan_ids of a
+// segment.
+type vlanRangesValidator struct{}
+
+func (v vlanRangesValidator) Description(_ context.Context) string {
+	return "values must be VLAN IDs between 0 and 4094, such as 1001, or ranges of them, such as 1001-1010"
+}
+
+func (v vlanRangesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v vlanRangesValidator) ValidateList(_ context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	for i, element := range req.ConfigValue.Elements() {
+		resp.Diagnostics.Append(v.validate(req.Path.AtListIndex(i), element)...)
+	}
+}
+
+func (v vlanRangesValidator) ValidateSet(_ context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+	for _, element := range req.ConfigValue.Elements() {
+		resp.Diagnostics.Append(v.validate(req.Path.AtSetValue(element), element)...)
+	}
+}
+
+func (v vlanRangesValidator) validate(p path.Path, element attr.Value) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	value, ok := element.(types.String)
+	if !ok || value.IsNull() || value.IsUnknown() {
+		return diags
+	}
+	if _, _, ok := parseVlanRange(value.ValueString()); !ok {
+		diags.AddAttributeError(
+			p,
+			"Invalid VLAN ID",
+			fmt.Sprintf("Expected a VLAN ID between 0 and 4094, such as 1001, or a range of them, such as 1001-1010. Got: %q", value.ValueString()),
+		)
+	}
+	return diags
+}
+
+var _ validator.String = oneOfStringValidator{}
+
+// oneOfStringValidator checks that a string attribute holds one of the
+// values of an NSX enum.
+type oneOfStringValidator struct {
+	values []string
+}
+
+func (v oneOfStringValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be one of %s", strings.Join(v.values, ", "))
+}
+
+func (v oneOfStringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v oneOfStringValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if !slices.Contains(v.values, req.ConfigValue.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+// urpfModeValidator checks the unicast reverse path forwarding mode of a
+// gateway interface.
+func urpfModeValidator() validator.String {
+	return oneOfStringValidator{values: []string{"STRICT", "NONE"}}
+}
+
+var _ validator.String = cidrValidator{}
+
+// cidrValidator checks that a string attribute holds a network in CIDR
+// notation, such as 192.168.0.0/16, without host bits set.
+type cidrValidator struct{}
+
+func (v cidrValidator) Description(_ context.Context) string {
+	return "value must be a network in CIDR notation, such as 192.168.0.0/16"
+}
+
+func (v cidrValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v cidrValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	ip, network, err := net.ParseCIDR(req.ConfigValue.ValueString())
+	if err != nil || !ip.Equal(network.IP) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+var _ validator.String = ipAddressValidator{}
+
+// ipAddressValidator checks that a string attribute holds an IPv4 or IPv6
+// address.
+type ipAddressValidator struct{}
+
+func (v ipAddressValidator) Description(_ context.Context) string {
+	return "value must be an IP address"
+}
+
+func (v ipAddressValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v ipAddressValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if net.ParseIP(req.ConfigValue.ValueString()) == nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+var _ validator.String = regexpValidator{}
+
+// regexpValidator checks that a string attribute holds a regular expression
+// in Go's RE2 syntax.
+type regexpValidator struct{}
+
+func (v regexpValidator) Description(_ context.Context) string {
+	return "value must be a valid regular expression"
+}
+
+func (v regexpValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v regexpValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := regexp.Compile(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %q: %s", req.Path, v.Description(ctx), req.ConfigValue.ValueString(), err),
+		)
+	}
+}
+
+var _ validator.Int64 = int64RangeValidator{}
+
+// int64RangeValidator checks that a number attribute is between min and
+// max.
+type int64RangeValidator struct {
+	min, max int64
+}
+
+func (v int64RangeValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be between %d and %d", v.min, v.max)
+}
+
+func (v int64RangeValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v int64RangeValidator) ValidateInt64(ctx context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if i := req.ConfigValue.ValueInt64(); i < v.min || i > v.max {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %d", req.Path, v.Description(ctx), i),
+		)
+	}
+}
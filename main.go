@@ -7,6 +7,7 @@ import (
 	"context"
 	"flag"
 	"log"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/technofish-au/terraform-provider-nsxt-intervlan-routing/internal/provider"
@@ -47,6 +48,12 @@ func main() {
 
 	err := providerserver.Serve(context.Background(), provider.New(version), opts)
 
+	// Log out of NSX before the process exits. Terraform only waits briefly
+	// for plugins to stop, so don't let this hold up shutdown.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	provider.Shutdown(ctx)
+	cancel()
+
 	if err != nil {
 		log.Fatal(err.Error())
 	}